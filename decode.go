@@ -7,13 +7,31 @@ package env
 import (
 	"bytes"
 	"io"
+	"io/fs"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/go-pogo/env/envtag"
+	"github.com/go-pogo/env/internal/osfs"
 	"github.com/go-pogo/errors"
 )
 
-const ErrStructPointerExpected errors.Msg = "expected a non-nil pointer to a struct"
+const (
+	ErrStructPointerExpected errors.Msg = "expected a non-nil pointer to a struct"
+	ErrMissingRequired       errors.Msg = "missing required environment variable"
+	ErrFileTooLarge          errors.Msg = "file exceeds maximum size"
+	ErrInvalidIndex          errors.Msg = "invalid slice index"
+)
+
+// MaxSliceIndex is the largest index of an element of a slice of structs
+// which is decoded from environment variables in the form of
+// {NAME}_{INDEX}_*.
+const MaxSliceIndex = 1<<16 - 1
+
+// DefaultMaxFileSize is the maximum size of files that are read for fields
+// with the file tag option, when [DecodeOptions.MaxFileSize] is 0.
+const DefaultMaxFileSize = 1 << 20
 
 // Unmarshaler is the interface implemented by types that can unmarshal a
 // textual representation of themselves.
@@ -30,8 +48,60 @@ func Unmarshal(data []byte, v any) error {
 }
 
 type DecodeOptions struct {
-	// ReplaceVars
+	// ReplaceVars replaces references to other environment variables, e.g.
+	// ${HOST} or $HOME, in looked up values and default values. References
+	// within default values resolve to the default values of other fields
+	// when their environment variables are not set.
 	ReplaceVars bool
+	// KeepNilPointers leaves nil pointers to structs nil, unless at least one
+	// of the fields of the struct resolves to a value or default. By default
+	// nil pointers are always allocated.
+	KeepNilPointers bool
+	// AllowEmpty uses explicitly empty values of environment variables as
+	// is, instead of falling back to their default values. Defaults are then
+	// only used when an environment variable is not set. This can also be
+	// enabled per field with the allowempty tag option.
+	AllowEmpty bool
+	// DisallowUnknown reports environment variables that start with
+	// UnknownPrefix but are not consumed by any of the fields of the decoded
+	// struct. This requires the Decoder's Lookupper to implement [Mapper].
+	DisallowUnknown bool
+	// UnknownPrefix is the prefix of the environment variables that are
+	// checked when DisallowUnknown is set. An empty prefix checks all
+	// environment variables.
+	UnknownPrefix string
+	// FS is the filesystem from which the files of fields with the file tag
+	// option are read. It defaults to the operating system's filesystem when
+	// nil.
+	FS fs.FS
+	// MaxFileSize is the maximum size in bytes of files that are read for
+	// fields with the file tag option. It defaults to [DefaultMaxFileSize]
+	// when 0, a negative value disables the limit.
+	MaxFileSize int64
+	// OnDeprecated is an optional callback which is called when the value of
+	// a field is looked up using one of its deprecated names.
+	OnDeprecated func(Deprecation)
+	// NoOverwrite leaves fields that already have a value untouched, so values
+	// from other sources, e.g. flags, are not overwritten by environment
+	// variables or default values. A field has a value when it is not its
+	// zero value, or when IsSet reports so when it is not nil. The values of
+	// these fields are still validated.
+	NoOverwrite bool
+	// IsSet is an optional predicate which reports if the field with
+	// environment variable key already has a value. It is used instead of
+	// checking for the zero value when NoOverwrite is set.
+	IsSet func(key string, rv reflect.Value) bool
+}
+
+// Deprecation describes the use of a deprecated environment variable name.
+type Deprecation struct {
+	// Name is the deprecated name of the environment variable.
+	Name string
+	// Replacement is the name of the environment variable that should be used
+	// instead.
+	Replacement string
+	// Field is the path to the struct field, e.g. Config.DB.Port.
+	Field string
 }
 
 // A Decoder looks up environment variables while decoding them into a struct.
@@ -40,6 +110,9 @@ type Decoder struct {
 	TagOptions
 
 	lookupper Lookupper
+	registry  *Registry
+	before    []FieldHook
+	after     []FieldHook
 }
 
 const panicNilLookupper = "env.Decoder: Lookupper must not be nil"
@@ -73,6 +146,14 @@ func (d *Decoder) Strict() *Decoder {
 	return d
 }
 
+// DisallowUnknownVars sets the DisallowUnknown option to true and
+// UnknownPrefix to prefix.
+func (d *Decoder) DisallowUnknownVars(prefix string) *Decoder {
+	d.DisallowUnknown = true
+	d.UnknownPrefix = prefix
+	return d
+}
+
 // WithLookupper sets the internal [Lookupper] to l.
 func (d *Decoder) WithLookupper(l Lookupper) *Decoder {
 	if l == nil {
@@ -83,6 +164,32 @@ func (d *Decoder) WithLookupper(l Lookupper) *Decoder {
 	return d
 }
 
+// WithRegistry sets the [Registry] which is used to unmarshal values. It
+// defaults to the package-global [Registry].
+func (d *Decoder) WithRegistry(r *Registry) *Decoder {
+	if r == nil {
+		panic(panicNilRegistry)
+	}
+
+	d.registry = r
+	return d
+}
+
+// WithBeforeHooks adds hooks to the chain of [FieldHook]s which are called
+// before the [Value] of a field is unmarshaled. Hooks are only called for
+// fields that resolve to a value or default.
+func (d *Decoder) WithBeforeHooks(hooks ...FieldHook) *Decoder {
+	d.before = append(d.before, hooks...)
+	return d
+}
+
+// WithAfterHooks adds hooks to the chain of [FieldHook]s which are called
+// after the [Value] of a field is unmarshaled, and before it is validated.
+func (d *Decoder) WithAfterHooks(hooks ...FieldHook) *Decoder {
+	d.after = append(d.after, hooks...)
+	return d
+}
+
 // WithOptions sets DecodeOptions to the provided [DecodeOptions] opts.
 func (d *Decoder) WithOptions(opts DecodeOptions) *Decoder {
 	d.DecodeOptions = opts
@@ -95,7 +202,30 @@ func (d *Decoder) WithTagOptions(opts TagOptions) *Decoder {
 	return d
 }
 
+// Decode looks up the environment variables for each of the fields of the
+// struct v points to and decodes their values into them. Decoding continues
+// when a value cannot be decoded, each failure is returned as a
+// *[DecodeError]. The final values of fields are validated against the rules
+// in their validate tag, also when no value is found, and (nested) structs
+// that implement [Validator] are validated after all their fields are decoded.
+// Failed validations are returned as a *[DecodeError] as well. When fields
+// that are marked as required remain empty, a *[MissingError] is returned
+// which lists all of them. When unknown environment variables are disallowed,
+// any that are found are returned as an *[UnknownError]. Multiple errors are
+// combined into a single error using [errors.Join].
 func (d *Decoder) Decode(v any) error {
+	return d.decode(v, nil)
+}
+
+// DecodeReport decodes v similar to [Decoder.Decode] and returns a [Report]
+// containing a [FieldReport] for each decoded field.
+func (d *Decoder) DecodeReport(v any) (Report, error) {
+	var report Report
+	err := d.decode(v, &report)
+	return report, err
+}
+
+func (d *Decoder) decode(v any, report *Report) error {
 	if d.lookupper == nil {
 		panic(panicNilLookupper)
 	}
@@ -114,24 +244,661 @@ func (d *Decoder) Decode(v any) error {
 		}
 	}
 
-	return (&traverser{
-		TagOptions:  d.TagOptions,
-		isKnownType: typeKnownByUnmarshaler,
-		handleField: d.decodeField,
+	state := decodeState{
+		Decoder: d,
+		reg:     registryOrGlobal(d.registry),
+		report:  report,
+		known:   make(map[string]struct{}, 8),
+	}
+	if d.ReplaceVars {
+		state.defaults = state.defaultsReplacer(rv.Type().Elem())
+	}
+	err := (&traverser{
+		TagOptions:   d.TagOptions,
+		isKnownType:  state.reg.knownByUnmarshaler,
+		handleField:  state.decodeField,
+		afterStruct:  state.validateStruct,
+		prepareSlice: state.prepareSlice,
+		prepareMap:   state.prepareMap,
+		prepareIface: state.prepareIface,
+		setElems:     true,
+		nilPtr:       state.nilPtr,
 	}).start(rv)
+	if report != nil {
+		report.redactSecretRefs()
+	}
+
+	if len(state.missing) != 0 {
+		state.errs = append(state.errs, errors.WithStack(&MissingError{
+			Vars: state.missing,
+		}))
+	}
+	if err != nil {
+		// decoding is stopped, keep the errors collected up until now
+		return errors.Join(append(state.errs, err)...)
+	}
+	if d.DisallowUnknown {
+		keys, err := state.keys(d.UnknownPrefix)
+		if err != nil {
+			return errors.Join(append(state.errs, err)...)
+		}
+		if vars := unknownVars(keys, state.known); len(vars) != 0 {
+			state.errs = append(state.errs, errors.WithStack(&UnknownError{
+				Vars: vars,
+			}))
+		}
+	}
+	return errors.Join(state.errs...)
 }
 
-func (d *Decoder) decodeField(rv reflect.Value, tag envtag.Tag) error {
-	val, err := d.lookupper.Lookup(tag.Name)
+// decodeState contains the state of a single [Decoder.Decode] call.
+type decodeState struct {
+	*Decoder
+	reg     *Registry
+	errs    []error
+	missing []MissingVar
+	report  *Report
+	// defaults replaces references in default values when ReplaceVars is set
+	defaults *Replacer
+	// known contains the names of all environment variables of the decoded
+	// fields
+	known map[string]struct{}
+	// resolved is the number of fields that resolved to a value or default
+	resolved int
+	// environ contains all environment variables of lookupper, it is lazily
+	// loaded using keys
+	environ Map
+}
+
+// keys returns all keys of the Decoder's Lookupper that start with prefix.
+func (d *decodeState) keys(prefix string) ([]string, error) {
+	if d.environ == nil {
+		m, err := environ(d.lookupper)
+		if err != nil {
+			return nil, err
+		}
+		d.environ = m
+	}
+
+	var keys []string
+	for k := range d.environ {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// prepareSlice discovers the indices of the elements of slice field rv by
+// looking for keys in the form of {NAME}_{INDEX}_*. It returns a slice with
+// the length of the discovered elements, or an invalid [reflect.Value] when
+// there are none. Existing elements are kept and decoded into, any elements
+// beyond the discovered ones are removed from the slice. An index must be a
+// decimal number without leading zeros, which is not larger than
+// [MaxSliceIndex], otherwise a [DecodeError] with [ErrInvalidIndex] is
+// recorded and the field is skipped. A gap in the indices is recorded as a
+// [DecodeError] with an [IndexGapError]. The field is skipped as well when
+// the Decoder's Lookupper cannot list its keys.
+func (d *decodeState) prepareSlice(rv reflect.Value, field fieldInfo) (reflect.Value, error) {
+	prefix := field.Tag.Name + "_"
+	keys, err := d.keys(prefix)
+	if err != nil {
+		if errors.Is(err, ErrNotMapper) {
+			return reflect.Value{}, nil
+		}
+		return reflect.Value{}, newDecodeError(rv, field, "", err)
+	}
+
+	indices := make(map[int]struct{}, len(keys))
+	n := 0
+	var invalid bool
+	for _, key := range keys {
+		index, _, _ := strings.Cut(key[len(prefix):], "_")
+		if index == "" || index[0] < '0' || index[0] > '9' {
+			// not an index, e.g. a field of a struct which has the same
+			// prefix
+			continue
+		}
+
+		i, err := strconv.ParseUint(index, 10, 0)
+		if err != nil || i > MaxSliceIndex || strconv.FormatUint(i, 10) != index {
+			d.errs = append(d.errs, errors.WithStack(&DecodeError{
+				Key:   key,
+				Field: field.Path,
+				Value: d.environ[key],
+				Type:  rv.Type(),
+				Err:   ErrInvalidIndex,
+			}))
+			invalid = true
+			continue
+		}
+		indices[int(i)] = struct{}{}
+		if int(i) >= n {
+			n = int(i) + 1
+		}
+	}
+	if n == 0 || invalid {
+		return reflect.Value{}, nil
+	}
+	if len(indices) != n {
+		gapErr := &IndexGapError{}
+		for i := 0; i < n; i++ {
+			if _, ok := indices[i]; !ok {
+				gapErr.Missing = append(gapErr.Missing, i)
+			}
+		}
+		d.errs = append(d.errs, newDecodeError(rv, field, "", gapErr))
+		return reflect.Value{}, nil
+	}
+
+	sv := indirect(rv)
+	if sv.Len() < n {
+		slice := reflect.MakeSlice(sv.Type(), n, n)
+		reflect.Copy(slice, sv)
+		sv.Set(slice)
+	} else if sv.Len() > n {
+		sv.Set(sv.Slice(0, n))
+	}
+	return sv, nil
+}
+
+// prepareMap discovers the entries of map field rv by looking for keys in the
+// form of {NAME}_{ENTRY}_{FIELD}, where FIELD is the name of one of the fields
+// of the map's element struct. Each distinct ENTRY is unmarshaled into a key
+// of the map. It returns the map, which is created when nil, together with
+// its entries sorted by name, or an invalid [reflect.Value] when there are
+// none or when the Decoder's Lookupper cannot list its keys.
+func (d *decodeState) prepareMap(rv reflect.Value, field fieldInfo) (reflect.Value, []mapEntry, error) {
+	prefix := field.Tag.Name + "_"
+	keys, err := d.keys(prefix)
+	if err != nil {
+		if errors.Is(err, ErrNotMapper) {
+			return reflect.Value{}, nil, nil
+		}
+		return reflect.Value{}, nil, newDecodeError(rv, field, "", err)
+	}
+
+	mt := rv.Type()
+	for mt.Kind() == reflect.Ptr {
+		mt = mt.Elem()
+	}
+
+	var suffixes []string
+	for _, tag := range d.fieldTags(mt.Elem()) {
+		suffixes = append(suffixes, tag.Name)
+	}
+	names := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if name := entryName(key[len(prefix):], suffixes); name != "" {
+			names[name] = struct{}{}
+		}
+	}
+	if len(names) == 0 {
+		return reflect.Value{}, nil, nil
+	}
+
+	entries := make([]mapEntry, 0, len(names))
+	for name := range names {
+		key := reflect.New(mt.Key()).Elem()
+		if err = d.reg.unmarshal(Value(name), key); err != nil {
+			d.errs = append(d.errs, newDecodeError(key, field, Value(name), err))
+			continue
+		}
+		entries = append(entries, mapEntry{name: name, key: key})
+	}
+	sortMapEntries(entries)
+
+	mv := indirect(rv)
+	if mv.IsNil() {
+		mv.Set(reflect.MakeMapWithSize(mt, len(entries)))
+	}
+	return mv, entries, nil
+}
+
+// prepareIface looks up the discriminator of interface field rv and returns
+// a zero value of the registered variant it selects, a pointer variant is
+// allocated. When the field already
+// contains a value of the variant, or when the discriminator is not set and
+// the field is not nil, the field's current value is returned instead.
+func (d *decodeState) prepareIface(rv reflect.Value, field fieldInfo) (reflect.Value, error) {
+	key := discriminatorKey(field.Tag)
+	d.known[key] = struct{}{}
+
+	val, err := d.lookupper.Lookup(key)
 	if err != nil && !IsNotFound(err) {
-		return err
+		return reflect.Value{}, errors.WithStack(&DecodeError{
+			Key:   key,
+			Field: field.Path,
+			Type:  rv.Type(),
+			Err:   err,
+		})
+	}
+	if !rv.IsNil() && (val == "" || d.isSet(rv, field.Tag)) {
+		return rv.Elem(), nil
+	}
+	if val == "" {
+		if field.Tag.Required {
+			d.missing = append(d.missing, MissingVar{
+				Name:  key,
+				Field: field.Path,
+			})
+		}
+		return reflect.Value{}, nil
+	}
+
+	typ, ok := d.reg.Variant(rv.Type(), val.String())
+	if !ok {
+		d.errs = append(d.errs, errors.WithStack(&DecodeError{
+			Key:   key,
+			Field: field.Path,
+			Value: val,
+			Type:  rv.Type(),
+			Err:   errors.New(ErrUnknownVariant),
+		}))
+		return reflect.Value{}, nil
 	}
-	if val.String() == "" {
-		if tag.Default == "" {
+
+	d.resolved++
+	if !rv.IsNil() && rv.Elem().Type() == typ && !isNilPtr(rv.Elem()) {
+		return rv.Elem(), nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		// the discriminator selected the variant, so it is allocated even
+		// when none of its fields resolve
+		return reflect.New(typ.Elem()), nil
+	}
+	return reflect.Zero(typ), nil
+}
+
+func isNilPtr(rv reflect.Value) bool {
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// fieldTags returns the tags of the fields of struct type typ, their names
+// are relative to the struct itself.
+func (d *decodeState) fieldTags(typ reflect.Type) []envtag.Tag {
+	var tags []envtag.Tag
+	_ = (&traverser{
+		TagOptions:  d.TagOptions,
+		isKnownType: d.reg.knownByUnmarshaler,
+		handleField: func(_ reflect.Value, field fieldInfo) error {
+			tags = append(tags, field.Tag)
 			return nil
+		},
+	}).start(reflect.New(typ))
+	return tags
+}
+
+// defaultsReplacer returns a [Replacer] which replaces references in default
+// values. References are resolved using the Decoder's Lookupper, with the
+// default values of the fields of struct type typ as fallback.
+func (d *decodeState) defaultsReplacer(typ reflect.Type) *Replacer {
+	defaults := make(Map, 8)
+	for _, tag := range d.fieldTags(typ) {
+		if tag.Default != "" {
+			defaults[tag.Name] = Value(tag.Default)
+		}
+	}
+
+	src := d.lookupper
+	if r, ok := src.(*Replacer); ok {
+		src = r.Unwrap()
+	}
+	return NewReplacer(Chain(src, defaults))
+}
+
+// entryName returns the name of the map entry within rest, which is the part
+// of an environment variable's name after the map field's prefix. It is the
+// part before the longest matching suffix, or an empty string when none of
+// the suffixes match.
+func entryName(rest string, suffixes []string) string {
+	var match string
+	for _, suffix := range suffixes {
+		if len(suffix) > len(match) && strings.HasSuffix(rest, "_"+suffix) {
+			match = suffix
 		}
+	}
+	if match == "" {
+		return ""
+	}
+	return rest[:len(rest)-len(match)-1]
+}
+
+// nilPtr traverses a newly allocated struct and reports if the nil pointer
+// to it should be set. With [DecodeOptions.KeepNilPointers] this is only the
+// case when at least one of its fields resolved, any errors of missing
+// required fields or failed validations of the struct are then discarded.
+func (d *decodeState) nilPtr(traverse func() error) (bool, error) {
+	if !d.KeepNilPointers {
+		return true, traverse()
+	}
+
+	resolved, errs, missing := d.resolved, len(d.errs), len(d.missing)
+	if err := traverse(); err != nil {
+		return false, err
+	}
+	if d.resolved > resolved {
+		return true, nil
+	}
+
+	d.errs, d.missing = d.errs[:errs], d.missing[:missing]
+	return false, nil
+}
+
+// lookup looks up the value of the field's environment variable. When it is
+// not found, the field's aliases and deprecated names are looked up in order.
+// It returns the name of the environment variable that is found.
+func (d *decodeState) lookup(field fieldInfo) (string, Value, error) {
+	tag := field.Tag
+	val, err := d.lookupper.Lookup(tag.Name)
+	if !IsNotFound(err) {
+		return tag.Name, val, err
+	}
+	for _, name := range tag.Aliases {
+		if val, err = d.lookupper.Lookup(name); !IsNotFound(err) {
+			return name, val, err
+		}
+	}
+	for _, name := range tag.Deprecated {
+		if val, err = d.lookupper.Lookup(name); IsNotFound(err) {
+			continue
+		}
+		if err == nil && d.OnDeprecated != nil {
+			d.OnDeprecated(Deprecation{
+				Name:        name,
+				Replacement: tag.Name,
+				Field:       field.Path,
+			})
+		}
+		return name, val, err
+	}
+	return tag.Name, "", err
+}
+
+// markKnown marks the field's name, aliases and deprecated names as known
+// environment variables.
+func (d *decodeState) markKnown(tag envtag.Tag) {
+	d.known[tag.Name] = struct{}{}
+	for _, name := range tag.Aliases {
+		d.known[name] = struct{}{}
+	}
+	for _, name := range tag.Deprecated {
+		d.known[name] = struct{}{}
+	}
+}
+
+// isSet indicates if field rv already has a value which should not be
+// overwritten.
+func (d *decodeState) isSet(rv reflect.Value, tag envtag.Tag) bool {
+	if !d.NoOverwrite {
+		return false
+	}
+	if d.IsSet != nil {
+		return d.IsSet(tag.Name, rv)
+	}
+	return !rv.IsZero()
+}
+
+func (d *decodeState) decodeField(rv reflect.Value, field fieldInfo) error {
+	tag := field.Tag
+	d.markKnown(tag)
+	if d.isSet(rv, tag) {
+		// keep the field's value, but make sure it is valid
+		d.validateField(rv, field, "")
+		return nil
+	}
+
+	key, val, err := d.lookup(field)
+	if err != nil && !IsNotFound(err) {
+		// the source is unable to provide values, there is no point in
+		// continuing with the remaining fields
+		return newDecodeError(rv, field, "", err)
+	}
+
+	set := err == nil
+	source := SourceNone
+	if val != "" || (set && (d.AllowEmpty || tag.AllowEmpty)) {
+		source = SourceEnviron
+	} else if tag.Default != "" {
 		val = Value(tag.Default)
+		source = SourceDefault
+
+		if d.defaults != nil {
+			if val, err = d.defaults.Replace(val); err != nil {
+				d.errs = append(d.errs, newDecodeError(rv, field, Value(tag.Default), err))
+				return nil
+			}
+		}
+	}
+	if d.report != nil {
+		fr := newFieldReport(d.lookupper, field, key, set, source, val)
+		if isSecret(rv.Type(), tag) {
+			fr.redact()
+		}
+		*d.report = append(*d.report, fr)
+	}
+
+	if source == SourceNone {
+		if tag.Required {
+			d.missing = append(d.missing, MissingVar{
+				Name:  tag.Name,
+				Field: field.Path,
+			})
+			return nil
+		}
+		// validate the field's current value, rules such as nonempty or min
+		// may not pass when there is no value
+		d.validateField(rv, field, val)
+		return nil
+	}
+
+	d.resolved++
+	if tag.File && val != "" {
+		path := val
+		if val, err = d.readFile(path.String()); err != nil {
+			d.errs = append(d.errs, newDecodeError(rv, field, path, err))
+			return nil
+		}
+	}
+	if tag.Trim {
+		val = Value(strings.TrimSpace(val.String()))
+	}
+	if len(d.before) != 0 {
+		hf := newHookField(rv, field, val)
+		skip, err := runHooks(d.before, hf)
+		if err != nil {
+			d.errs = append(d.errs, newDecodeError(rv, field, val, err))
+			return nil
+		} else if skip {
+			return nil
+		}
+		val = hf.Value
+	}
+	if val == "" {
+		// an explicitly empty value is allowed
+		rv.Set(reflect.Zero(rv.Type()))
+	} else if err = d.reg.unmarshalField(val, rv, tag); err != nil {
+		d.errs = append(d.errs, newDecodeError(rv, field, val, err))
+		return nil
 	}
+	if len(d.after) != 0 {
+		skip, err := runHooks(d.after, newHookField(rv, field, val))
+		if err != nil {
+			d.errs = append(d.errs, newDecodeError(rv, field, val, err))
+			return nil
+		} else if skip {
+			return nil
+		}
+	}
+	d.validateField(rv, field, val)
+	return nil
+}
+
+// validateField validates the final value of field rv against the rules of
+// its validate tag. val is the raw [Value] the field is decoded from, if any.
+func (d *decodeState) validateField(rv reflect.Value, field fieldInfo, val Value) {
+	if field.Tag.Validate == "" {
+		return
+	}
+	if err := validate(d.reg, rv, field.Tag.Validate); err != nil {
+		d.errs = append(d.errs, newDecodeError(rv, field, val, err))
+	}
+}
+
+// readFile reads the contents of the file at path from the Decoder's FS.
+func (d *decodeState) readFile(path string) (_ Value, err error) {
+	fsys := d.FS
+	if fsys == nil {
+		fsys = osfs.FS{}
+	}
+
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer errors.AppendFunc(&err, f.Close)
+
+	var r io.Reader = f
+	limit := d.MaxFileSize
+	if limit == 0 {
+		limit = DefaultMaxFileSize
+	}
+	if limit > 0 {
+		// read one more byte to detect files that exceed the limit
+		r = io.LimitReader(f, limit+1)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if limit > 0 && int64(len(b)) > limit {
+		return "", errors.New(ErrFileTooLarge)
+	}
+	return Value(b), nil
+}
+
+func (d *decodeState) validateStruct(rv reflect.Value, field fieldInfo) error {
+	if err := callValidator(rv); err != nil {
+		d.errs = append(d.errs, newDecodeError(rv, field, "", err))
+	}
+	return nil
+}
+
+// IndexGapError is the error of a [DecodeError] when the indices of the
+// environment variables of a slice of structs are not sequential.
+type IndexGapError struct {
+	// Missing contains the missing indices.
+	Missing []int
+}
 
-	return unmarshaler.Unmarshal(val, rv)
+func (e *IndexGapError) Error() string {
+	var buf strings.Builder
+	buf.WriteString("gap in slice indices, missing index ")
+	for i, index := range e.Missing {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.Itoa(index))
+	}
+	return buf.String()
+}
+
+// DecodeError is returned by [Decoder.Decode] when the [Value] of an
+// environment variable cannot be decoded into its struct field.
+type DecodeError struct {
+	// Key is the name of the environment variable.
+	Key string
+	// Field is the path to the struct field, e.g. Config.DB.Port.
+	Field string
+	// Value is the raw [Value] that failed to decode.
+	Value Value
+	// Type is the type of the struct field.
+	Type reflect.Type
+	// Secret indicates Value is sensitive, it is redacted in the error
+	// message. Err is then replaced by an error which does not mention the
+	// value, but still matches the underlying error using [errors.Is].
+	Secret bool
+	// Err is the underlying error.
+	Err error
+}
+
+func newDecodeError(rv reflect.Value, field fieldInfo, val Value, err error) error {
+	secret := isSecret(rv.Type(), field.Tag)
+	if secret && err != nil {
+		err = &secretError{cause: err}
+	}
+	return errors.WithStack(&DecodeError{
+		Key:    field.Tag.Name,
+		Field:  field.Path,
+		Value:  val,
+		Type:   rv.Type(),
+		Secret: secret,
+		Err:    err,
+	})
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+func (e *DecodeError) Error() string {
+	var buf strings.Builder
+	buf.WriteString("error while decoding `")
+	buf.WriteString(e.Key)
+	buf.WriteRune('=')
+	if e.Secret {
+		buf.WriteString(redact(e.Value.String()))
+	} else {
+		buf.WriteString(e.Value.String())
+	}
+	buf.WriteString("` into ")
+	if e.Field != "" {
+		buf.WriteString(e.Field)
+		buf.WriteRune(' ')
+	}
+	buf.WriteRune('(')
+	if e.Type != nil {
+		buf.WriteString(e.Type.String())
+	}
+	buf.WriteRune(')')
+	if e.Err != nil {
+		buf.WriteString(": ")
+		buf.WriteString(e.Err.Error())
+	}
+	return buf.String()
+}
+
+// MissingVar describes a required environment variable which has no value.
+type MissingVar struct {
+	// Name of the environment variable.
+	Name string
+	// Field is the path to the struct field, e.g. Config.DB.Port.
+	Field string
+}
+
+// MissingError is returned by [Decoder.Decode] when one or more environment
+// variables of fields that are marked as required have no value.
+type MissingError struct {
+	Vars []MissingVar
+}
+
+// Is returns true when target is [ErrMissingRequired].
+func (e *MissingError) Is(target error) bool { return target == ErrMissingRequired }
+
+func (e *MissingError) Error() string {
+	var buf strings.Builder
+	buf.WriteString(ErrMissingRequired.String())
+	if len(e.Vars) > 1 {
+		buf.WriteRune('s')
+	}
+	buf.WriteString(": ")
+	for i, v := range e.Vars {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(v.Name)
+		buf.WriteString(" (")
+		buf.WriteString(v.Field)
+		buf.WriteRune(')')
+	}
+	return buf.String()
 }
@@ -5,9 +5,14 @@
 package env
 
 import (
+	"io/fs"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/go-pogo/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -179,6 +184,316 @@ func TestDecoder_Decode(t *testing.T) {
 		assert.Exactly(t, want, have)
 	})
 
+	t.Run("required", func(t *testing.T) {
+		type nested struct {
+			Port int `env:",required"`
+		}
+		type Config struct {
+			Host   string `env:",required"`
+			Name   string `env:",required" default:"app"`
+			Token  string `env:"API_TOKEN,required"`
+			Nested nested `env:"DB"`
+		}
+
+		var have Config
+		err := NewReaderDecoder(strings.NewReader("HOST=localhost")).Decode(&have)
+		assert.ErrorIs(t, err, ErrMissingRequired)
+		assert.Exactly(t, Config{Host: "localhost", Name: "app"}, have)
+
+		var missingErr *MissingError
+		assert.ErrorAs(t, err, &missingErr)
+		assert.Exactly(t, []MissingVar{
+			{Name: "API_TOKEN", Field: "Config.Token"},
+			{Name: "DB_PORT", Field: "Config.Nested.Port"},
+		}, missingErr.Vars)
+		assert.Equal(t,
+			"missing required environment variables: API_TOKEN (Config.Token), DB_PORT (Config.Nested.Port)",
+			missingErr.Error(),
+		)
+	})
+
+	t.Run("decode errors", func(t *testing.T) {
+		type nested struct {
+			Port int
+		}
+		type Config struct {
+			Debug   bool
+			Name    string
+			Timeout time.Duration
+			DB      nested
+			Token   string `env:",required"`
+		}
+
+		const input = "DEBUG=yes please\nNAME=app\nTIMEOUT=10s\nDB_PORT=abc"
+
+		var have Config
+		err := NewReaderDecoder(strings.NewReader(input)).Decode(&have)
+		assert.Exactly(t, Config{Name: "app", Timeout: 10 * time.Second}, have)
+
+		var multiErr errors.MultiError
+		assert.ErrorAs(t, err, &multiErr)
+		errs := multiErr.Unwrap()
+		assert.Len(t, errs, 3)
+		assert.ErrorIs(t, err, ErrMissingRequired)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, errs[0], &decErr)
+		assert.Equal(t, "DEBUG", decErr.Key)
+		assert.Equal(t, "Config.Debug", decErr.Field)
+		assert.Equal(t, Value("yes please"), decErr.Value)
+		assert.Equal(t, reflect.TypeOf(true), decErr.Type)
+
+		assert.ErrorAs(t, errs[1], &decErr)
+		assert.Equal(t, "DB_PORT", decErr.Key)
+		assert.Equal(t, "Config.DB.Port", decErr.Field)
+		assert.Equal(t, Value("abc"), decErr.Value)
+		assert.Equal(t, reflect.TypeOf(0), decErr.Type)
+		assert.Contains(t, decErr.Error(), "error while decoding `DB_PORT=abc` into Config.DB.Port (int): ")
+	})
+
+	t.Run("slice of structs", func(t *testing.T) {
+		type backend struct {
+			Host string
+			Port int `default:"80"`
+		}
+		type Config struct {
+			Backends []backend
+			Ptrs     []*backend `env:"PTR"`
+		}
+
+		src := Map{
+			"BACKENDS_0_HOST": "a.local",
+			"BACKENDS_0_PORT": "8080",
+			"BACKENDS_1_HOST": "b.local",
+			"PTR_0_HOST":      "c.local",
+		}
+		want := Config{
+			Backends: []backend{
+				{Host: "a.local", Port: 8080},
+				{Host: "b.local", Port: 80},
+			},
+			Ptrs: []*backend{{Host: "c.local", Port: 80}},
+		}
+
+		var have Config
+		assert.NoError(t, NewDecoder(src).Decode(&have))
+		assert.Exactly(t, want, have)
+
+		t.Run("chain", func(t *testing.T) {
+			var have Config
+			dec := NewDecoder(Map{"BACKENDS_1_HOST": "b.local"}, src)
+			assert.NoError(t, dec.Decode(&have))
+			assert.Exactly(t, want, have)
+		})
+		t.Run("gap", func(t *testing.T) {
+			tests := map[string]struct {
+				src  Map
+				want []int
+			}{
+				"single missing": {
+					src:  Map{"BACKENDS_0_HOST": "a.local", "BACKENDS_2_HOST": "c.local"},
+					want: []int{1},
+				},
+				"missing first": {
+					src:  Map{"BACKENDS_1_HOST": "b.local"},
+					want: []int{0},
+				},
+				"multiple missing": {
+					src: Map{
+						"BACKENDS_0_HOST": "a.local",
+						"BACKENDS_0_PORT": "8080",
+						"BACKENDS_3_HOST": "d.local",
+						"BACKENDS_3_PORT": "8083",
+					},
+					want: []int{1, 2},
+				},
+			}
+			for name, tc := range tests {
+				t.Run(name, func(t *testing.T) {
+					var have Config
+					err := NewDecoder(tc.src).Decode(&have)
+					assert.NotErrorIs(t, err, ErrInvalidIndex)
+
+					var gapErr *IndexGapError
+					assert.ErrorAs(t, err, &gapErr)
+					assert.Exactly(t, tc.want, gapErr.Missing)
+					assert.ErrorContains(t, err, "BACKENDS")
+					assert.Nil(t, have.Backends)
+				})
+			}
+		})
+		t.Run("invalid index", func(t *testing.T) {
+			tests := map[string]Map{
+				"too large": {
+					"BACKENDS_0_HOST":           "a.local",
+					"BACKENDS_99999999999_HOST": "x.local",
+				},
+				"leading zero": {
+					"BACKENDS_0_HOST":  "a.local",
+					"BACKENDS_01_HOST": "x.local",
+				},
+			}
+			for name, src := range tests {
+				t.Run(name, func(t *testing.T) {
+					var have Config
+					err := NewDecoder(src).Decode(&have)
+
+					var decErr *DecodeError
+					assert.ErrorAs(t, err, &decErr)
+					assert.ErrorIs(t, err, ErrInvalidIndex)
+					assert.Equal(t, "Config.Backends", decErr.Field)
+					assert.Equal(t, Value("x.local"), decErr.Value)
+					assert.Nil(t, have.Backends)
+				})
+			}
+		})
+		t.Run("existing", func(t *testing.T) {
+			have := Config{Backends: []backend{
+				{Host: "x.local", Port: 8080},
+				{Host: "y.local"},
+				{Host: "z.local"},
+			}}
+			assert.NoError(t, NewDecoder(Map{"BACKENDS_0_HOST": "a.local"}).Decode(&have))
+			assert.Exactly(t, []backend{{Host: "a.local", Port: 80}}, have.Backends)
+		})
+		t.Run("not a mapper", func(t *testing.T) {
+			var have Config
+			err := NewDecoder(LookupperFunc(src.Lookup)).Decode(&have)
+			assert.NoError(t, err)
+			assert.Nil(t, have.Backends)
+			assert.Nil(t, have.Ptrs)
+		})
+	})
+
+	t.Run("map of structs", func(t *testing.T) {
+		type db struct {
+			Host     string
+			Port     int `default:"5432"`
+			ReadOnly bool
+		}
+		type Config struct {
+			DB   map[string]db
+			Ptrs map[string]*db `env:"PTR"`
+		}
+
+		src := Map{
+			"DB_PRIMARY_HOST":         "primary.local",
+			"DB_PRIMARY_PORT":         "5433",
+			"DB_REPLICA_HOST":         "replica.local",
+			"DB_REPLICA_READ_ONLY":    "true",
+			"DB_EU_WEST_HOST":         "eu.local",
+			"DB_UNRELATED":            "foo",
+			"PTR_MAIN_HOST":           "main.local",
+			"PTR_MAIN_PORT":           "6543",
+			"DB_PRIMARY_HOST_UNKNOWN": "bar",
+		}
+		want := Config{
+			DB: map[string]db{
+				"PRIMARY": {Host: "primary.local", Port: 5433},
+				"REPLICA": {Host: "replica.local", Port: 5432, ReadOnly: true},
+				"EU_WEST": {Host: "eu.local", Port: 5432},
+			},
+			Ptrs: map[string]*db{
+				"MAIN": {Host: "main.local", Port: 6543},
+			},
+		}
+
+		var have Config
+		assert.NoError(t, NewDecoder(src).Decode(&have))
+		assert.Exactly(t, want, have)
+
+		t.Run("existing", func(t *testing.T) {
+			have := Config{DB: map[string]db{
+				"OTHER":   {Host: "other.local"},
+				"PRIMARY": {ReadOnly: true},
+			}}
+			assert.NoError(t, NewDecoder(Map{"DB_PRIMARY_HOST": "primary.local"}).Decode(&have))
+			assert.Exactly(t, map[string]db{
+				"OTHER":   {Host: "other.local"},
+				"PRIMARY": {Host: "primary.local", Port: 5432, ReadOnly: true},
+			}, have.DB)
+		})
+		t.Run("invalid key", func(t *testing.T) {
+			var have struct {
+				DB map[int]db
+			}
+			err := NewDecoder(Map{
+				"DB_1_HOST":   "one.local",
+				"DB_TWO_HOST": "two.local",
+			}).Decode(&have)
+
+			var decErr *DecodeError
+			assert.ErrorAs(t, err, &decErr)
+			assert.Exactly(t, Value("TWO"), decErr.Value)
+			assert.Exactly(t, map[int]db{1: {Host: "one.local", Port: 5432}}, have.DB)
+		})
+		t.Run("not a mapper", func(t *testing.T) {
+			var have Config
+			err := NewDecoder(LookupperFunc(src.Lookup)).Decode(&have)
+			assert.NoError(t, err)
+			assert.Nil(t, have.DB)
+			assert.Nil(t, have.Ptrs)
+		})
+	})
+
+	t.Run("keep nil pointers", func(t *testing.T) {
+		type tls struct {
+			Cert string `env:",required"`
+			Key  string
+		}
+		type server struct {
+			Port int `default:"8080"`
+		}
+		type Config struct {
+			TLS    *tls
+			Server *server
+			Other  *tls
+		}
+
+		src := Map{"TLS_CERT": "cert.pem"}
+
+		var have Config
+		dec := NewDecoder(src).WithOptions(DecodeOptions{KeepNilPointers: true})
+		assert.NoError(t, dec.Decode(&have))
+		assert.Exactly(t, Config{
+			TLS:    &tls{Cert: "cert.pem"},
+			Server: &server{Port: 8080},
+		}, have)
+
+		t.Run("disabled", func(t *testing.T) {
+			var have Config
+			err := NewDecoder(src).Decode(&have)
+			assert.ErrorIs(t, err, ErrMissingRequired)
+			assert.NotNil(t, have.Other)
+		})
+	})
+
+	t.Run("allow empty", func(t *testing.T) {
+		type Config struct {
+			Proxy   string `default:"proxy.local"`
+			Timeout int    `default:"10"`
+			Host    string `env:",allowempty" default:"localhost"`
+			Port    int    `default:"8080"`
+		}
+
+		src := Map{
+			"PROXY":   "",
+			"TIMEOUT": "",
+			"HOST":    "",
+		}
+
+		var have Config
+		assert.NoError(t, NewDecoder(src).Decode(&have))
+		assert.Exactly(t, Config{Proxy: "proxy.local", Timeout: 10, Port: 8080}, have)
+
+		t.Run("option", func(t *testing.T) {
+			var have Config
+			dec := NewDecoder(src).WithOptions(DecodeOptions{AllowEmpty: true})
+			assert.NoError(t, dec.Decode(&have))
+			assert.Exactly(t, Config{Port: 8080}, have)
+		})
+	})
+
 	t.Run("nil", func(t *testing.T) {
 		assert.ErrorIs(t,
 			NewDecoder(System()).Decode(nil),
@@ -216,3 +531,361 @@ func TestDecoder_Decode(t *testing.T) {
 		})
 	})
 }
+
+func TestDecoder_Decode_lookupError(t *testing.T) {
+	type Config struct {
+		Port int
+		Name string `env:",required"`
+		Host string
+	}
+
+	src := Map{"PORT": "abc"}
+	wantErr := errors.New("source unavailable")
+
+	var have Config
+	err := NewDecoder(LookupperFunc(func(key string) (Value, error) {
+		if key == "HOST" {
+			return "", wantErr
+		}
+		return src.Lookup(key)
+	})).Decode(&have)
+
+	assert.ErrorIs(t, err, wantErr)
+	assert.ErrorIs(t, err, ErrMissingRequired)
+
+	var decErr *DecodeError
+	assert.ErrorAs(t, err, &decErr)
+	assert.Equal(t, "PORT", decErr.Key)
+}
+
+func TestDecoder_DecodeReport(t *testing.T) {
+	type Config struct {
+		Host  string `default:"localhost"`
+		Port  int    `default:"8080"`
+		Addr  string
+		Proxy string
+	}
+
+	first := Map{"PORT": "1234"}
+	second := Map{
+		"HOST":  "",
+		"PORT":  "4321",
+		"ADDR":  "local:${PORT}",
+		"OTHER": "foo",
+	}
+
+	var have Config
+	report, err := NewDecoder(first, second).DecodeReport(&have)
+	assert.NoError(t, err)
+	assert.Exactly(t, Report{
+		{
+			Name:   "HOST",
+			Field:  "Config.Host",
+			Set:    true,
+			Source: SourceDefault,
+			Raw:    "localhost",
+			Value:  "localhost",
+		},
+		{
+			Name:      "PORT",
+			Field:     "Config.Port",
+			Set:       true,
+			Source:    SourceEnviron,
+			Lookupper: first,
+			Raw:       "1234",
+			Value:     "1234",
+		},
+		{
+			Name:      "ADDR",
+			Field:     "Config.Addr",
+			Set:       true,
+			Source:    SourceEnviron,
+			Expanded:  true,
+			Lookupper: second,
+			Raw:       "local:${PORT}",
+			Value:     "local:1234",
+		},
+		{Name: "PROXY", Field: "Config.Proxy"},
+	}, report)
+
+	assert.True(t, report.IsSet("Config.Host"))
+	assert.False(t, report.IsSet("Config.Proxy"))
+	assert.False(t, report.IsSet("Config.Unknown"))
+}
+
+func TestDecoder_DisallowUnknownVars(t *testing.T) {
+	type backend struct {
+		URL  string
+		Pool int `default:"4"`
+	}
+	type Config struct {
+		App struct {
+			DatabaseURL string `env:"APP_DATABASE_URL"`
+			Backend     []backend
+			Debug       bool
+		}
+	}
+
+	src := Map{
+		"APP_DATABASE_URL":    "postgres://localhost",
+		"APP_DATABSE_URL":     "postgres://typo",
+		"APP_BACKEND_0_URL":   "http://a.local",
+		"APP_BACKEND_0_POOLS": "2",
+		"APP_SOMETHING_ELSE":  "bar",
+		"OTHER":               "ignored",
+	}
+
+	var have Config
+	err := NewDecoder(src).DisallowUnknownVars("APP_").Decode(&have)
+	assert.ErrorIs(t, err, ErrUnknownVar)
+	assert.Equal(t, "postgres://localhost", have.App.DatabaseURL)
+
+	var unknownErr *UnknownError
+	assert.ErrorAs(t, err, &unknownErr)
+	assert.Exactly(t, []UnknownVar{
+		{Name: "APP_BACKEND_0_POOLS", Suggestion: "APP_BACKEND_0_POOL"},
+		{Name: "APP_DATABSE_URL", Suggestion: "APP_DATABASE_URL"},
+		{Name: "APP_SOMETHING_ELSE"},
+	}, unknownErr.Vars)
+	assert.ErrorContains(t, err, "APP_DATABSE_URL (did you mean APP_DATABASE_URL?)")
+
+	t.Run("all known", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(Map{"APP_DEBUG": "true", "OTHER": "foo"}).
+			DisallowUnknownVars("APP_").
+			Decode(&have)
+		assert.NoError(t, err)
+	})
+	t.Run("not a mapper", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(LookupperFunc(src.Lookup)).
+			DisallowUnknownVars("").
+			Decode(&have)
+		assert.ErrorIs(t, err, ErrNotMapper)
+	})
+}
+
+func TestDecoder_Decode_aliases(t *testing.T) {
+	type Config struct {
+		Host string `env:"HOST,alias=HOSTNAME,alias=SERVER_HOST"`
+		Port int    `env:"PORT,alias=HTTP_PORT,deprecated=OLD_PORT"`
+		User string `env:"USER,deprecated=OLD_USER,deprecated=LEGACY_USER"`
+	}
+
+	src := Map{
+		"SERVER_HOST": "server.local",
+		"HTTP_PORT":   "8080",
+		"OLD_PORT":    "1234",
+		"LEGACY_USER": "root",
+	}
+
+	var deprecations []Deprecation
+	dec := NewDecoder(src).WithOptions(DecodeOptions{
+		OnDeprecated: func(dep Deprecation) {
+			deprecations = append(deprecations, dep)
+		},
+	})
+
+	var have Config
+	report, err := dec.DecodeReport(&have)
+	assert.NoError(t, err)
+	assert.Exactly(t, Config{Host: "server.local", Port: 8080, User: "root"}, have)
+	assert.Exactly(t, []Deprecation{{
+		Name:        "LEGACY_USER",
+		Replacement: "USER",
+		Field:       "Config.User",
+	}}, deprecations)
+
+	fr, _ := report.Lookup("Config.Host")
+	assert.Equal(t, "HOST", fr.Name)
+	assert.Equal(t, "SERVER_HOST", fr.Alias)
+
+	t.Run("canonical name first", func(t *testing.T) {
+		var have Config
+		assert.NoError(t, NewDecoder(Map{
+			"HOST":     "canonical.local",
+			"HOSTNAME": "alias.local",
+		}).Decode(&have))
+		assert.Equal(t, "canonical.local", have.Host)
+	})
+	t.Run("known", func(t *testing.T) {
+		var have Config
+		assert.NoError(t, NewDecoder(src).DisallowUnknownVars("").Decode(&have))
+	})
+	t.Run("encode canonical", func(t *testing.T) {
+		var buf strings.Builder
+		assert.NoError(t, NewEncoder(&buf).Encode(have))
+		assert.Equal(t, "HOST=\nPORT=0\nUSER=\n", buf.String())
+	})
+}
+
+func TestDecoder_Decode_file(t *testing.T) {
+	type Config struct {
+		Cert    string `env:"TLS_CERT,file"`
+		Key     Secret `env:"TLS_KEY,file,trim"`
+		License string `env:",file" default:"license.txt"`
+		Port    int    `env:",file,trim"`
+	}
+
+	fsys := fstest.MapFS{
+		"tls/cert.pem": &fstest.MapFile{Data: []byte("-----CERT-----\n")},
+		"tls/key.pem":  &fstest.MapFile{Data: []byte("  -----KEY-----\n")},
+		"license.txt":  &fstest.MapFile{Data: []byte("license")},
+		"port":         &fstest.MapFile{Data: []byte("8080\n")},
+	}
+	src := Map{
+		"TLS_CERT": "tls/cert.pem",
+		"TLS_KEY":  "tls/key.pem",
+		"PORT":     "port",
+	}
+
+	var have Config
+	dec := NewDecoder(src).WithOptions(DecodeOptions{FS: fsys})
+	assert.NoError(t, dec.Decode(&have))
+	assert.Exactly(t, Config{
+		Cert:    "-----CERT-----\n",
+		Key:     "-----KEY-----",
+		License: "license",
+		Port:    8080,
+	}, have)
+
+	t.Run("not exists", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(Map{"TLS_CERT": "nope.pem"}).
+			WithOptions(DecodeOptions{FS: fsys}).
+			Decode(&have)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.Equal(t, "TLS_CERT", decErr.Key)
+		assert.Equal(t, Value("nope.pem"), decErr.Value)
+	})
+	t.Run("too large", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(src).
+			WithOptions(DecodeOptions{FS: fsys, MaxFileSize: 10}).
+			Decode(&have)
+
+		assert.ErrorIs(t, err, ErrFileTooLarge)
+		assert.Equal(t, 8080, have.Port)
+	})
+	t.Run("no limit", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(src).
+			WithOptions(DecodeOptions{FS: fsys, MaxFileSize: -1}).
+			Decode(&have)
+		assert.NoError(t, err)
+	})
+}
+
+func TestDecoder_Decode_expandDefaults(t *testing.T) {
+	type Config struct {
+		Addr  string `default:"${HOST}:${PORT}"`
+		Host  string `default:"localhost"`
+		Port  int    `default:"8080"`
+		Cache string `default:"$HOME/.cache/app"`
+		Other string `default:"$UNKNOWN"`
+	}
+
+	src := Map{
+		"HOME": "/home/user",
+		"PORT": "1234",
+	}
+
+	var have Config
+	report, err := NewDecoder(src).DecodeReport(&have)
+	assert.NoError(t, err)
+	assert.Exactly(t, Config{
+		Addr:  "localhost:1234",
+		Host:  "localhost",
+		Port:  1234,
+		Cache: "/home/user/.cache/app",
+		Other: "$UNKNOWN",
+	}, have)
+
+	fr, _ := report.Lookup("Config.Addr")
+	assert.Equal(t, SourceDefault, fr.Source)
+	assert.True(t, fr.Expanded)
+	assert.Equal(t, Value("${HOST}:${PORT}"), fr.Raw)
+	assert.Equal(t, Value("localhost:1234"), fr.Value)
+
+	t.Run("disabled", func(t *testing.T) {
+		var have Config
+		assert.NoError(t, NewDecoder(src).WithOptions(DecodeOptions{}).Decode(&have))
+		assert.Equal(t, "${HOST}:${PORT}", have.Addr)
+	})
+	t.Run("circular", func(t *testing.T) {
+		var have struct {
+			Foo string `default:"$BAR"`
+			Bar string `default:"$FOO"`
+		}
+		err := NewDecoder(Map{}).Decode(&have)
+		assert.ErrorIs(t, err, ErrCircularDependency)
+	})
+}
+
+func TestDecoder_Decode_noOverwrite(t *testing.T) {
+	type Config struct {
+		Host    string `default:"localhost"`
+		Port    int    `default:"8080"`
+		Debug   bool   `env:"DEBUG,required"`
+		Timeout time.Duration
+	}
+
+	src := Map{"PORT": "1234", "TIMEOUT": "5s"}
+
+	t.Run("zero", func(t *testing.T) {
+		have := Config{Port: 80, Debug: true}
+		assert.NoError(t, NewDecoder(src).
+			WithOptions(DecodeOptions{NoOverwrite: true}).
+			Decode(&have),
+		)
+		assert.Exactly(t, Config{
+			Host:    "localhost",
+			Port:    80,
+			Debug:   true,
+			Timeout: 5 * time.Second,
+		}, have)
+	})
+	t.Run("predicate", func(t *testing.T) {
+		// Debug is explicitly set to false, e.g. with a flag
+		set := map[string]bool{"DEBUG": true, "HOST": true}
+		have := Config{Host: "example.com"}
+		assert.NoError(t, NewDecoder(src).
+			WithOptions(DecodeOptions{
+				NoOverwrite: true,
+				IsSet: func(key string, _ reflect.Value) bool {
+					return set[key]
+				},
+			}).
+			Decode(&have),
+		)
+		assert.Exactly(t, Config{
+			Host:    "example.com",
+			Port:    1234,
+			Timeout: 5 * time.Second,
+		}, have)
+	})
+	t.Run("validate", func(t *testing.T) {
+		type Config struct {
+			Port int `default:"8080" validate:"min=1024"`
+		}
+
+		have := Config{Port: 80}
+		err := NewDecoder(src).
+			WithOptions(DecodeOptions{NoOverwrite: true}).
+			Decode(&have)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+		assert.Equal(t, "PORT", decErr.Key)
+		assert.Equal(t, 80, have.Port)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		have := Config{Port: 80, Debug: true}
+		assert.ErrorIs(t, NewDecoder(src).Decode(&have), ErrMissingRequired)
+		assert.Equal(t, 1234, have.Port)
+	})
+}
@@ -26,8 +26,10 @@ func (e *NoFilesLoadedError) Error() string {
 }
 
 var (
-	_ env.LookupMapper = (*Reader)(nil)
-	_ io.Closer        = (*Reader)(nil)
+	_ env.LookupMapper    = (*Reader)(nil)
+	_ env.OrderedMapper   = (*Reader)(nil)
+	_ env.OriginLookupper = (*Reader)(nil)
+	_ io.Closer           = (*Reader)(nil)
 )
 
 // A Reader reads .env files from a filesystem and provides the mechanism to
@@ -132,12 +134,35 @@ func (r *Reader) Lookup(key string) (env.Value, error) {
 		return v, nil
 	}
 
+	v, _, err := r.lookup(key)
+	return v, err
+}
+
+// LookupOrigin returns the name of the .env file which contains key. When
+// multiple files contain key, it is the file with the highest precedence.
+func (r *Reader) LookupOrigin(key string) (string, error) {
+	r.init(nil, "")
+	_, f, err := r.lookup(key)
+	if err != nil {
+		return "", err
+	}
+
+	if r.dir != "" {
+		return r.fsys.JoinFilePath(r.dir, f.name), nil
+	}
+	return f.name, nil
+}
+
+// lookup key by reading from .env files, starting with the file with the
+// highest precedence. It returns the value and the file containing it.
+func (r *Reader) lookup(key string) (env.Value, *file, error) {
 	var anyLoaded bool
 	for i := len(r.files) - 1; i >= 0; i-- {
-		fr, exists, err := r.fileReader(r.files[i])
+		f := r.files[i]
+		fr, exists, err := r.fileReader(f)
 		anyLoaded = anyLoaded || exists
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		if fr == nil {
 			continue
@@ -148,24 +173,34 @@ func (r *Reader) Lookup(key string) (env.Value, error) {
 			if env.IsNotFound(err) {
 				continue
 			}
-			return v, err
+			return v, nil, err
 		}
-		return v, nil
+		return v, f, nil
 	}
 	if !anyLoaded {
-		return "", errors.WithStack(&NoFilesLoadedError{FS: r.fsys, Dir: r.dir})
+		return "", nil, errors.WithStack(&NoFilesLoadedError{FS: r.fsys, Dir: r.dir})
 	}
 
-	return "", errors.New(env.ErrNotFound)
+	return "", nil, errors.New(env.ErrNotFound)
 }
 
 // Environ reads and returns all environment variables from the loaded .env
 // files.
 func (r *Reader) Environ() (env.Map, error) {
+	om, err := r.OrderedEnviron()
+	if om == nil {
+		return nil, err
+	}
+	return om.Map(), err
+}
+
+// OrderedEnviron reads and returns all environment variables from the loaded
+// .env files, in the order in which they are first defined.
+func (r *Reader) OrderedEnviron() (*env.OrderedMap, error) {
 	r.init(nil, "")
 	var anyLoaded bool
 
-	res := make(env.Map, 8)
+	res := env.NewOrderedMap()
 	for _, f := range r.files {
 		fr, exists, err := r.fileReader(f)
 		anyLoaded = anyLoaded || exists
@@ -176,18 +211,18 @@ func (r *Reader) Environ() (env.Map, error) {
 			continue
 		}
 
-		m, err := fr.Environ()
+		om, err := fr.OrderedEnviron()
 		if err != nil {
 			return nil, err
 		}
 
-		res.MergeValues(m)
+		res.Merge(om)
 	}
 	if !anyLoaded {
 		return nil, errors.WithStack(&NoFilesLoadedError{FS: r.fsys, Dir: r.dir})
 	}
 
-	r.found.MergeValues(res)
+	r.found.MergeValues(res.Map())
 	return res, nil
 }
 
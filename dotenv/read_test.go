@@ -74,6 +74,36 @@ func TestReader_Lookup(t *testing.T) {
 	}
 }
 
+func TestReader_LookupOrigin(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf/.env": &fstest.MapFile{
+			Data: []byte("FOO=BAR\nQUX=XOO"),
+		},
+		"conf/.env.dev": &fstest.MapFile{
+			Data: []byte("FOO=BAZ"),
+		},
+	}
+
+	r := ReadFS(fsys, "conf", Development)
+	have, haveErr := r.LookupOrigin("FOO")
+	assert.NoError(t, haveErr)
+	assert.Equal(t, "conf/.env.dev", have)
+
+	have, haveErr = r.LookupOrigin("QUX")
+	assert.NoError(t, haveErr)
+	assert.Equal(t, "conf/.env", have)
+
+	_, haveErr = r.LookupOrigin("NOPE")
+	assert.ErrorIs(t, haveErr, env.ErrNotFound)
+
+	t.Run("report", func(t *testing.T) {
+		var cfg struct{ Foo string }
+		report, err := env.NewDecoder(r).DecodeReport(&cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "conf/.env.dev", report[0].Origin)
+	})
+}
+
 func TestReader_Environ(t *testing.T) {
 	fsys := fstest.MapFS{
 		".env.prod": &fstest.MapFile{
@@ -116,3 +146,22 @@ func TestReader_Environ(t *testing.T) {
 		})
 	}
 }
+
+func TestReader_OrderedEnviron(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env": &fstest.MapFile{
+			Data: []byte("FOO=BAR\nQUX=XOO"),
+		},
+		".env.dev": &fstest.MapFile{
+			Data: []byte("BAR=BAZ\nFOO=BAZ"),
+		},
+	}
+
+	have, haveErr := ReadFS(fsys, "", Development).OrderedEnviron()
+	assert.NoError(t, haveErr)
+	assert.Equal(t, []env.NamedValue{
+		{Name: "FOO", Value: "BAZ"},
+		{Name: "QUX", Value: "XOO"},
+		{Name: "BAR", Value: "BAZ"},
+	}, have.NamedValues())
+}
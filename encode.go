@@ -40,6 +40,13 @@ type EncodeOptions struct {
 	ExportPrefix bool
 
 	Formatter Formatter
+	// KeyOrder determines the order in which the keys of a map are encoded.
+	// It defaults to [SortKeys] when nil.
+	KeyOrder KeyOrder
+	// RevealSecrets encodes the actual values of fields that are secrets,
+	// instead of [Redacted]. Fields are secrets when they are of type [Secret]
+	// or have the secret tag option.
+	RevealSecrets bool
 }
 
 // An Encoder writes env values to an output stream.
@@ -47,7 +54,10 @@ type Encoder struct {
 	EncodeOptions
 	TagOptions
 
-	w writing.StringWriter
+	w        writing.StringWriter
+	registry *Registry
+	before   []FieldHook
+	after    []FieldHook
 }
 
 const panicNilWriter = "env.Encoder: io.Writer must not be nil"
@@ -59,9 +69,12 @@ func NewEncoder(w io.Writer) *Encoder {
 	}
 
 	return &Encoder{
-		EncodeOptions: EncodeOptions{Formatter: Format},
-		TagOptions:    envtag.DefaultOptions(),
-		w:             writing.ToStringWriter(w),
+		EncodeOptions: EncodeOptions{
+			Formatter: Format,
+			KeyOrder:  SortKeys,
+		},
+		TagOptions: envtag.DefaultOptions(),
+		w:          writing.ToStringWriter(w),
 	}
 }
 
@@ -77,12 +90,47 @@ func (e *Encoder) WithTagOptions(opts TagOptions) *Encoder {
 	return e
 }
 
+// WithRegistry sets the [Registry] which is used to marshal values. It
+// defaults to the package-global [Registry].
+func (e *Encoder) WithRegistry(r *Registry) *Encoder {
+	if r == nil {
+		panic(panicNilRegistry)
+	}
+
+	e.registry = r
+	return e
+}
+
+// WithBeforeHooks adds hooks to the chain of [FieldHook]s which are called
+// before the value of a field is marshaled. A hook may replace the value by
+// changing [HookField.Target]. Returning [SkipField] omits the field from the
+// output.
+func (e *Encoder) WithBeforeHooks(hooks ...FieldHook) *Encoder {
+	e.before = append(e.before, hooks...)
+	return e
+}
+
+// WithAfterHooks adds hooks to the chain of [FieldHook]s which are called
+// after the value of a field is marshaled, and before it is written. A hook
+// may replace the marshaled [HookField.Value]. Returning [SkipField] omits the
+// field from the output.
+func (e *Encoder) WithAfterHooks(hooks ...FieldHook) *Encoder {
+	e.after = append(e.after, hooks...)
+	return e
+}
+
 // WithFormatter sets Formatter to the provided [Formatter] p.
 func (e *Encoder) WithFormatter(p Formatter) *Encoder {
 	e.Formatter = p
 	return e
 }
 
+// WithKeyOrder sets KeyOrder to the provided [KeyOrder] o.
+func (e *Encoder) WithKeyOrder(o KeyOrder) *Encoder {
+	e.KeyOrder = o
+	return e
+}
+
 // WithWriter changes the internal [io.Writer] to w.
 func (e *Encoder) WithWriter(w io.Writer) *Encoder {
 	if w == nil {
@@ -93,10 +141,12 @@ func (e *Encoder) WithWriter(w io.Writer) *Encoder {
 }
 
 // Encode writes the env format encoding of v to the underlying [io.Writer].
+// The keys of maps are written in the order determined by KeyOrder.
 // Supported types of v are:
 //   - [Map]
 //   - map[string][Value]
 //   - map[[fmt.Stringer]][Value]
+//   - *[OrderedMap]
 //   - [][NamedValue]
 //   - [][envtag.Tag]
 //   - any struct type the rawconv package can handle
@@ -110,26 +160,27 @@ func (e *Encoder) Encode(v any) (err error) {
 		}
 	}
 
+	if e.KeyOrder == nil {
+		e.KeyOrder = SortKeys
+	}
+
 	switch src := v.(type) {
 	case Map:
-		for key, val := range src {
-			if err = e.print(key, val); err != nil {
-				return err
-			}
-		}
-		return nil
+		return e.encodeMap(src)
 
 	case map[string]Value:
-		for key, val := range src {
-			if err = e.print(key, val); err != nil {
-				return err
-			}
-		}
-		return nil
+		return e.encodeMap(src)
 
 	case map[fmt.Stringer]Value:
+		m := make(Map, len(src))
 		for key, val := range src {
-			if err = e.print(key.String(), val); err != nil {
+			m[key.String()] = val
+		}
+		return e.encodeMap(m)
+
+	case *OrderedMap:
+		for _, key := range src.keys {
+			if err = e.print(key, src.vals[key]); err != nil {
 				return err
 			}
 		}
@@ -137,7 +188,11 @@ func (e *Encoder) Encode(v any) (err error) {
 
 	case []NamedValue:
 		for _, nv := range src {
-			if err = e.print(nv.Name, nv.Value); err != nil {
+			val := nv.Value
+			if nv.Secret && !e.RevealSecrets {
+				val = Value(redact(val.String()))
+			}
+			if err = e.print(nv.Name, val); err != nil {
 				return err
 			}
 		}
@@ -157,26 +212,120 @@ func (e *Encoder) Encode(v any) (err error) {
 			return errors.New(ErrStructExpected)
 		}
 
+		reg := registryOrGlobal(e.registry)
 		return (&traverser{
-			TagOptions:  e.TagOptions,
-			isKnownType: typeKnownByMarshaler,
-			handleField: e.encodeField,
+			TagOptions:   e.TagOptions,
+			isKnownType:  reg.knownByMarshaler,
+			handleField:  e.encodeField,
+			prepareSlice: valueSlice,
+			prepareMap:   reg.valueMap,
+			prepareIface: e.valueIface,
 		}).start(rv)
 	}
 }
 
-func (e *Encoder) encodeField(rv reflect.Value, tag envtag.Tag) error {
-	if !e.TakeValues && tag.Default == "" {
-		return e.print(tag.Name, reflect.New(rv.Type()).Elem())
+func (e *Encoder) encodeMap(m Map) error {
+	for i, group := range e.KeyOrder(m.Keys()) {
+		if i > 0 {
+			if _, err := e.w.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+		for _, key := range group {
+			if err := e.print(key, m[key]); err != nil {
+				return err
+			}
+		}
 	}
-	if tag.Default != "" && (!e.TakeValues || (e.TakeValues && rv.IsZero())) {
+	return nil
+}
+
+func (e *Encoder) encodeField(rv reflect.Value, field fieldInfo) error {
+	reg := registryOrGlobal(e.registry)
+	tag := field.Tag
+	if !e.TakeValues && tag.Default == "" {
+		rv = reflect.New(rv.Type()).Elem()
+	} else if tag.Default != "" && (!e.TakeValues || (e.TakeValues && rv.IsZero())) {
 		var err error
-		if rv, err = defaultValue(rv.Type(), tag.DefaultValue()); err != nil {
+		if rv, err = reg.defaultValue(rv.Type(), tag); err != nil {
 			return err
 		}
 	}
+	if len(e.before) != 0 {
+		hf := newHookField(rv, field, "")
+		if skip, err := runHooks(e.before, hf); err != nil || skip {
+			return err
+		}
+		rv = hf.Target
+	}
+	if !e.RevealSecrets && isSecret(rv.Type(), tag) && !rv.IsZero() {
+		return e.print(tag.Name, Value(Redacted))
+	}
+	if len(e.after) == 0 && e.registry == nil && tag.Converter == "" && tag.Encoding == "" && !isListType(rv.Type(), reg.knownByMarshaler) {
+		return e.print(tag.Name, rv)
+	}
 
-	return e.print(tag.Name, rv)
+	// marshal using the Encoder's Registry, the field's converter or the
+	// separators from the tag so the Formatter and after hooks receive the
+	// value in its final form
+	val, err := reg.marshalField(rv, tag)
+	if err != nil {
+		return err
+	}
+	if len(e.after) != 0 {
+		hf := newHookField(rv, field, val)
+		if skip, err := runHooks(e.after, hf); err != nil || skip {
+			return err
+		}
+		val = hf.Value
+	}
+	return e.print(tag.Name, val)
+}
+
+// valueSlice returns the slice value of rv, or an invalid [reflect.Value] when
+// rv is a nil pointer.
+func valueSlice(rv reflect.Value, _ fieldInfo) (reflect.Value, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, nil
+		}
+		rv = rv.Elem()
+	}
+	return rv, nil
+}
+
+// valueMap returns the map value of rv together with its entries sorted by
+// name, or an invalid [reflect.Value] when rv is a nil pointer.
+func (r *Registry) valueMap(rv reflect.Value, _ fieldInfo) (reflect.Value, []mapEntry, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	keys := rv.MapKeys()
+	entries := make([]mapEntry, 0, len(keys))
+	for _, key := range keys {
+		name, err := r.marshal(key)
+		if err != nil {
+			return reflect.Value{}, nil, err
+		}
+		entries = append(entries, mapEntry{name: name.String(), key: key})
+	}
+	sortMapEntries(entries)
+	return rv, entries, nil
+}
+
+// valueIface writes the name of the variant of interface field rv to the
+// field's discriminator environment variable and returns its concrete value.
+// When rv is nil, an empty discriminator is written and the field is skipped.
+func (e *Encoder) valueIface(rv reflect.Value, field fieldInfo) (reflect.Value, error) {
+	el, name, err := registryOrGlobal(e.registry).variantValue(rv, field)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return el, e.print(discriminatorKey(field.Tag), Value(name))
 }
 
 func (e *Encoder) print(name string, val any) error {
@@ -191,8 +340,10 @@ func (e *Encoder) print(name string, val any) error {
 	return nil
 }
 
-func defaultValue(t reflect.Type, v Value) (reflect.Value, error) {
-	ptr := reflect.New(t)
-	err := unmarshaler.Unmarshal(v, ptr)
-	return ptr.Elem(), err
+// defaultValue returns a new value of type t with the default value of tag
+// unmarshaled into it.
+func (r *Registry) defaultValue(t reflect.Type, tag envtag.Tag) (reflect.Value, error) {
+	rv := reflect.New(t).Elem()
+	err := r.unmarshalField(tag.DefaultValue(), rv, tag)
+	return rv, err
 }
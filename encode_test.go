@@ -60,6 +60,16 @@ func TestEncoder(t *testing.T) {
 				`qux='"xoo"'`,
 			},
 		},
+		"OrderedMap": {
+			input: NewOrderedMap(
+				NamedValue{Name: `qux`, Value: `xoo`},
+				NamedValue{Name: `foo`, Value: `bar`},
+			),
+			want: []string{
+				`qux=xoo`,
+				`foo=bar`,
+			},
+		},
 		"NamedValues": {
 			input: []NamedValue{
 				{Name: `foo`, Value: `12.3`},
@@ -179,6 +189,133 @@ func TestEncoder(t *testing.T) {
 	})
 }
 
+func TestEncoder_Encode_sliceOfStructs(t *testing.T) {
+	type backend struct {
+		Host string
+		Port int `default:"80"`
+	}
+	type subj struct {
+		Backends []backend
+		Nil      *[]backend
+	}
+
+	var buf strings.Builder
+	enc := NewEncoder(&buf)
+	enc.TakeValues = true
+
+	assert.NoError(t, enc.Encode(subj{Backends: []backend{
+		{Host: "a.local", Port: 8080},
+		{Host: "b.local"},
+	}}))
+	assert.Equal(t, "BACKENDS_0_HOST=a.local\nBACKENDS_0_PORT=8080\nBACKENDS_1_HOST=b.local\nBACKENDS_1_PORT=80\n", buf.String())
+
+	var have subj
+	assert.NoError(t, Unmarshal([]byte(buf.String()), &have))
+	assert.Exactly(t, []backend{
+		{Host: "a.local", Port: 8080},
+		{Host: "b.local", Port: 80},
+	}, have.Backends)
+}
+
+func TestEncoder_Encode_mapOfStructs(t *testing.T) {
+	type db struct {
+		Host string
+		Port int `default:"5432"`
+	}
+	type subj struct {
+		DB  map[string]db
+		Nil *map[string]db
+	}
+
+	var buf strings.Builder
+	enc := NewEncoder(&buf)
+	enc.TakeValues = true
+
+	assert.NoError(t, enc.Encode(subj{DB: map[string]db{
+		"REPLICA": {Host: "replica.local"},
+		"PRIMARY": {Host: "primary.local", Port: 5433},
+	}}))
+	assert.Equal(t, "DB_PRIMARY_HOST=primary.local\nDB_PRIMARY_PORT=5433\nDB_REPLICA_HOST=replica.local\nDB_REPLICA_PORT=5432\n", buf.String())
+
+	var have subj
+	assert.NoError(t, Unmarshal([]byte(buf.String()), &have))
+	assert.Exactly(t, map[string]db{
+		"PRIMARY": {Host: "primary.local", Port: 5433},
+		"REPLICA": {Host: "replica.local", Port: 5432},
+	}, have.DB)
+
+	t.Run("round trip", func(t *testing.T) {
+		type tls struct {
+			CertFile string
+		}
+		type server struct {
+			Host string
+			TLS  tls
+		}
+		type nested struct {
+			Servers map[string]server
+		}
+		type subj struct {
+			Servers map[string]server
+			Nested  nested
+		}
+
+		want := subj{
+			Servers: map[string]server{
+				"primary":   {Host: "primary.local", TLS: tls{CertFile: "primary.pem"}},
+				"eu-west":   {Host: "eu.local"},
+				"ReadOnly1": {Host: "ro.local"},
+			},
+			Nested: nested{Servers: map[string]server{
+				"a": {Host: "a.local"},
+			}},
+		}
+
+		var buf strings.Builder
+		enc := NewEncoder(&buf)
+		enc.TakeValues = true
+		assert.NoError(t, enc.Encode(want))
+		assert.Equal(t, `SERVERS_ReadOnly1_HOST=ro.local
+SERVERS_ReadOnly1_TLS_CERT_FILE=
+SERVERS_eu-west_HOST=eu.local
+SERVERS_eu-west_TLS_CERT_FILE=
+SERVERS_primary_HOST=primary.local
+SERVERS_primary_TLS_CERT_FILE=primary.pem
+NESTED_SERVERS_a_HOST=a.local
+NESTED_SERVERS_a_TLS_CERT_FILE=
+`, buf.String())
+
+		var have subj
+		assert.NoError(t, Unmarshal([]byte(buf.String()), &have))
+		assert.Exactly(t, want, have)
+	})
+}
+
+func TestEncoder_Encode_nilPointers(t *testing.T) {
+	type server struct {
+		Host string
+		Port int `default:"8080"`
+	}
+	type subj struct {
+		Server *server
+		Nested **server
+	}
+
+	var buf strings.Builder
+	enc := NewEncoder(&buf)
+	enc.TakeValues = true
+
+	have := subj{}
+	assert.NoError(t, enc.Encode(have))
+	assert.Equal(t, "SERVER_HOST=\nSERVER_PORT=8080\nNESTED_HOST=\nNESTED_PORT=8080\n", buf.String())
+	assert.Nil(t, have.Server)
+
+	buf.Reset()
+	assert.NoError(t, enc.Encode(&have))
+	assert.Nil(t, have.Server)
+	assert.Nil(t, have.Nested)
+}
+
 func assertSimilarOutput(t *testing.T, have string, want []string) {
 	assert.Len(t, have, 1+len(strings.Join(want, "\n")))
 	for _, line := range want {
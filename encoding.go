@@ -0,0 +1,113 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/env/envtag"
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrUnknownEncoding errors.Msg = "unknown encoding"
+	ErrBytesExpected   errors.Msg = "expected a byte slice"
+)
+
+// EncodeBytes encodes b using encoding, which is one of
+// [envtag.EncodingBase64], [envtag.EncodingBase64URL] or [envtag.EncodingHex].
+// The resulting [Value] can be formatted using [Format].
+func EncodeBytes(b []byte, encoding string) (Value, error) {
+	switch encoding {
+	case envtag.EncodingBase64:
+		return Value(base64.StdEncoding.EncodeToString(b)), nil
+	case envtag.EncodingBase64URL:
+		return Value(base64.URLEncoding.EncodeToString(b)), nil
+	case envtag.EncodingHex:
+		return Value(hex.EncodeToString(b)), nil
+	default:
+		return "", errors.Wrap(ErrUnknownEncoding, encoding)
+	}
+}
+
+// DecodeBytes decodes val using encoding, which is one of
+// [envtag.EncodingBase64], [envtag.EncodingBase64URL] or [envtag.EncodingHex].
+// Base64 encoded values may omit their padding, a value which is padded must
+// be padded correctly.
+func DecodeBytes(val Value, encoding string) ([]byte, error) {
+	var b []byte
+	var err error
+
+	str := val.String()
+	padded := strings.HasSuffix(str, "=")
+	switch encoding {
+	case envtag.EncodingBase64:
+		if padded {
+			b, err = base64.StdEncoding.DecodeString(str)
+		} else {
+			b, err = base64.RawStdEncoding.DecodeString(str)
+		}
+	case envtag.EncodingBase64URL:
+		if padded {
+			b, err = base64.URLEncoding.DecodeString(str)
+		} else {
+			b, err = base64.RawURLEncoding.DecodeString(str)
+		}
+	case envtag.EncodingHex:
+		b, err = hex.DecodeString(str)
+	default:
+		return nil, errors.Wrap(ErrUnknownEncoding, encoding)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// isBytesType indicates if typ is a (pointer to a) byte slice.
+func isBytesType(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+// unmarshalBytes decodes val using encoding and sets the result to byte slice
+// rv.
+func unmarshalBytes(val Value, rv reflect.Value, encoding string) error {
+	if !isBytesType(rv.Type()) {
+		return errors.New(ErrBytesExpected)
+	}
+
+	b, err := DecodeBytes(val, encoding)
+	if err != nil {
+		return err
+	}
+
+	rv = indirect(rv)
+	rv.SetBytes(b)
+	return nil
+}
+
+// marshalBytes encodes byte slice rv using encoding.
+func marshalBytes(rv reflect.Value, encoding string) (Value, error) {
+	if !isBytesType(rv.Type()) {
+		return "", errors.New(ErrBytesExpected)
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Len() == 0 {
+		return "", nil
+	}
+	return EncodeBytes(rv.Bytes(), encoding)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-pogo/env/envtag"
+	"github.com/stretchr/testify/assert"
+)
+
+var binaryKey = []byte{0xfb, 0xff, 0x00, 0x10, 0x3e}
+
+func TestEncodeBytes(t *testing.T) {
+	tests := map[string]Value{
+		envtag.EncodingBase64:    "+/8AED4=",
+		envtag.EncodingBase64URL: "-_8AED4=",
+		envtag.EncodingHex:       "fbff00103e",
+	}
+	for encoding, want := range tests {
+		t.Run(encoding, func(t *testing.T) {
+			have, err := EncodeBytes(binaryKey, encoding)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+
+			b, err := DecodeBytes(have, encoding)
+			assert.NoError(t, err)
+			assert.Equal(t, binaryKey, b)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := EncodeBytes(binaryKey, "base32")
+		assert.ErrorIs(t, err, ErrUnknownEncoding)
+		_, err = DecodeBytes("", "base32")
+		assert.ErrorIs(t, err, ErrUnknownEncoding)
+	})
+}
+
+func TestDecodeBytes(t *testing.T) {
+	tests := map[string]struct {
+		encoding string
+		input    Value
+		wantErr  bool
+	}{
+		"base64 without padding":    {encoding: envtag.EncodingBase64, input: "+/8AED4"},
+		"base64url without padding": {encoding: envtag.EncodingBase64URL, input: "-_8AED4"},
+		"hex uppercase":             {encoding: envtag.EncodingHex, input: "FBFF00103E"},
+		"base64 with padding":       {encoding: envtag.EncodingBase64, input: "+/8AED4="},
+		"base64url with padding":    {encoding: envtag.EncodingBase64URL, input: "-_8AED4="},
+		"invalid base64":            {encoding: envtag.EncodingBase64, input: "-_8AED4", wantErr: true},
+		"invalid base64 padding":    {encoding: envtag.EncodingBase64, input: "+/8AED4===", wantErr: true},
+		"invalid base64url padding": {encoding: envtag.EncodingBase64URL, input: "AAAA=====", wantErr: true},
+		"invalid hex":               {encoding: envtag.EncodingHex, input: "fbff0", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := DecodeBytes(tc.input, tc.encoding)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, binaryKey, have)
+		})
+	}
+}
+
+type binaryConfig struct {
+	Key     []byte  `env:",encoding=base64"`
+	HMAC    []byte  `env:",encoding=hex,secret"`
+	Token   *[]byte `env:",encoding=base64url"`
+	Invalid string  `env:",encoding=hex"`
+}
+
+func TestDecoder_Decode_encoding(t *testing.T) {
+	var have binaryConfig
+	assert.NoError(t, NewDecoder(Map{
+		"KEY":   "+/8AED4=",
+		"HMAC":  "fbff00103e",
+		"TOKEN": "-_8AED4",
+	}).Decode(&have))
+
+	assert.Equal(t, binaryKey, have.Key)
+	assert.Equal(t, binaryKey, have.HMAC)
+	assert.Equal(t, binaryKey, *have.Token)
+
+	t.Run("errors", func(t *testing.T) {
+		var have binaryConfig
+		err := NewDecoder(Map{
+			"KEY":     "not base64!",
+			"INVALID": "fbff",
+		}).Decode(&have)
+
+		assert.ErrorIs(t, err, ErrBytesExpected)
+		assert.ErrorContains(t, err, "`KEY=not base64!` into binaryConfig.Key ([]uint8)")
+	})
+}
+
+func TestEncoder_Encode_encoding(t *testing.T) {
+	type config struct {
+		Key   []byte `env:",encoding=base64"`
+		HMAC  []byte `env:",encoding=hex,secret"`
+		Empty []byte `env:",encoding=base64url"`
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, NewEncoder(&buf).
+		WithOptions(EncodeOptions{TakeValues: true, RevealSecrets: true}).
+		Encode(config{Key: binaryKey, HMAC: binaryKey}),
+	)
+	assert.Equal(t, "KEY=+/8AED4=\nHMAC=fbff00103e\nEMPTY=\n", buf.String())
+
+	var have config
+	assert.NoError(t, Unmarshal(buf.Bytes(), &have))
+	assert.Equal(t, config{Key: binaryKey, HMAC: binaryKey}, have)
+
+	t.Run("format", func(t *testing.T) {
+		val, err := EncodeBytes(binaryKey, envtag.EncodingBase64)
+		assert.NoError(t, err)
+
+		have, err := Format("KEY", val)
+		assert.NoError(t, err)
+		assert.Equal(t, "KEY=+/8AED4=", have)
+	})
+}
@@ -6,7 +6,6 @@ package env
 
 import (
 	"os"
-	"strings"
 
 	"github.com/go-pogo/env/envtag"
 	"github.com/go-pogo/errors"
@@ -51,19 +50,6 @@ func (o osEnv) Lookup(key string) (Value, error) {
 }
 
 // Environ returns a [Map] with the environment variables using [os.Environ].
-func Environ() Map {
-	env := os.Environ()
-	res := make(Map, len(env))
-
-	for _, e := range env {
-		if e[0] == '=' {
-			continue
-		}
-
-		i := strings.IndexRune(e, '=')
-		res[e[:i]] = Value(e[i+1:])
-	}
-	return res
-}
+func Environ() Map { return FromEnviron(os.Environ()) }
 
 func (o osEnv) Environ() (Map, error) { return Environ(), nil }
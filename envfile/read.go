@@ -14,8 +14,9 @@ import (
 )
 
 var (
-	_ env.LookupMapper = (*Reader)(nil)
-	_ io.Closer        = (*Reader)(nil)
+	_ env.LookupMapper    = (*Reader)(nil)
+	_ env.OriginLookupper = (*Reader)(nil)
+	_ io.Closer           = (*Reader)(nil)
 )
 
 // reader prevents Reader from needing to have a public *Reader
@@ -24,6 +25,7 @@ type reader = env.Reader
 type Reader struct {
 	*reader
 	file fs.File
+	name string
 }
 
 // NewReader returns a [Reader] which looks up environment variables from
@@ -59,7 +61,28 @@ func OpenFS(fsys fs.FS, filename string) (*Reader, error) {
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	return NewReader(f), nil
+
+	r := NewReader(f)
+	r.name = filename
+	return r, nil
+}
+
+// LookupOrigin returns the name of the file when it contains key. This is the
+// filename provided to [Open] or [OpenFS], or the name reported by the file's
+// [fs.FileInfo] otherwise.
+func (f *Reader) LookupOrigin(key string) (string, error) {
+	if _, err := f.Lookup(key); err != nil {
+		return "", err
+	}
+	if f.name != "" {
+		return f.name, nil
+	}
+
+	stat, err := f.file.Stat()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return stat.Name(), nil
 }
 
 // Close closes the underlying [fs.File].
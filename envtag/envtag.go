@@ -9,12 +9,22 @@ import (
 	"github.com/go-pogo/rawconv"
 )
 
+// Supported binary encodings of byte slice values.
+const (
+	EncodingBase64    = "base64"
+	EncodingBase64URL = "base64url"
+	EncodingHex       = "hex"
+)
+
 type Tag struct {
 	// Name of the tag which will be used to construct the environment
 	// variable's full name.
 	Name string
 	// Default is an optional default value for the environment variable.
 	Default string
+	// Validate is an optional string of comma separated validation rules the
+	// decoded value must pass.
+	Validate string
 	// Ignore indicates the field should be ignored.
 	Ignore bool
 	// Inline indicates the field will be "flattened" when it is a struct. This
@@ -24,13 +34,58 @@ type Tag struct {
 	// Include indicates the structs child fields should be included, even if
 	// they do not have an env tag and Options.StrictTag is set to true.
 	Include bool
+	// Separator is an optional separator which is used to split and join the
+	// items of slices, arrays and maps. It defaults to a comma.
+	Separator string
+	// KeyValueSeparator is an optional separator which is used to split and
+	// join the keys and values of map items. It defaults to an equal sign.
+	KeyValueSeparator string
+	// Required indicates the environment variable must have a non-empty value
+	// after decoding.
+	Required bool
+	// AllowEmpty indicates an explicitly empty value of the environment
+	// variable is used as is. The default value is then only used when the
+	// environment variable is not set.
+	AllowEmpty bool
+	// Aliases are optional alternate names of the environment variable, which
+	// are looked up in order when Name is not found.
+	Aliases []string
+	// Deprecated are optional deprecated names of the environment variable,
+	// which are looked up in order when neither Name nor any of the Aliases
+	// are found.
+	Deprecated []string
+	// Secret indicates the value of the environment variable is sensitive and
+	// should be redacted whenever possible.
+	Secret bool
+	// File indicates the value of the environment variable is a path to a
+	// file of which the contents are used as the actual value.
+	File bool
+	// Trim indicates any leading and trailing whitespace is trimmed from the
+	// value. This is especially useful in combination with File.
+	Trim bool
+	// Converter is the optional name of a registered converter which is used
+	// to (un)marshal the value, instead of the converter for the field's
+	// type.
+	Converter string
+	// Discriminator is the optional name of the environment variable, relative
+	// to Name, of which the value selects the registered variant an interface
+	// field is decoded into.
+	Discriminator string
+	// Encoding is the optional binary encoding of the value of a byte slice,
+	// which is one of [EncodingBase64], [EncodingBase64URL] or [EncodingHex].
+	Encoding string
 }
 
 func (t Tag) DefaultValue() rawconv.Value { return rawconv.Value(t.Default) }
 
 // IsEmpty indicates if [Tag] is considered empty.
 func (t Tag) IsEmpty() bool {
-	return t.Name == "" && !t.Ignore && !t.Inline && !t.Include
+	return t.Name == "" && !t.Ignore && !t.Inline && !t.Include &&
+		t.Separator == "" && t.KeyValueSeparator == "" &&
+		!t.Required && !t.AllowEmpty &&
+		len(t.Aliases) == 0 && len(t.Deprecated) == 0 && !t.Secret &&
+		!t.File && !t.Trim && t.Converter == "" && t.Discriminator == "" &&
+		t.Encoding == ""
 }
 
 // ShouldIgnore indicates if [Tag] should be ignored.
@@ -25,6 +25,9 @@ type Options struct {
 	// DefaultKey is used to look up an optional default value string from a
 	// [reflect.StructTag].
 	DefaultKey string
+	// ValidateKey is used to look up an optional string of validation rules
+	// from a [reflect.StructTag].
+	ValidateKey string
 	// Normalizer is used to normalize a [reflect.StructField]'s name
 	// when no name is provided in the env tag string.
 	Normalizer Normalizer
@@ -35,10 +38,11 @@ type Options struct {
 // DefaultOptions returns an [Options] with default values.
 func DefaultOptions() Options {
 	return Options{
-		EnvKey:     EnvKey,
-		DefaultKey: DefaultKey,
-		Normalizer: defaultNormalizer,
-		StrictTags: false,
+		EnvKey:      EnvKey,
+		DefaultKey:  DefaultKey,
+		ValidateKey: ValidateKey,
+		Normalizer:  defaultNormalizer,
+		StrictTags:  false,
 	}
 }
 
@@ -12,8 +12,9 @@ import (
 )
 
 const (
-	EnvKey     = "env"
-	DefaultKey = "default"
+	EnvKey      = "env"
+	DefaultKey  = "default"
+	ValidateKey = "validate"
 )
 
 type Error struct {
@@ -74,6 +75,9 @@ func ParseStructField(opts Options, field reflect.StructField, prefix string) (t
 	if opts.DefaultKey != "" {
 		tag.Default = field.Tag.Get(opts.DefaultKey)
 	}
+	if opts.ValidateKey != "" {
+		tag.Validate = field.Tag.Get(opts.ValidateKey)
+	}
 	return
 }
 
@@ -92,13 +96,37 @@ func parse(tag *Tag, str string) error {
 	split = split[1:]
 
 	for i, n := 0, len(split); i < n; {
-		switch strings.TrimSpace(split[i]) {
-		case "":
+		switch opt := strings.TrimSpace(split[i]); {
+		case opt == "":
 			// empty option is ignored
-		case "inline":
+		case opt == "inline":
 			tag.Inline = true
-		case "include":
+		case opt == "include":
 			tag.Include = true
+		case opt == "required":
+			tag.Required = true
+		case opt == "allowempty":
+			tag.AllowEmpty = true
+		case opt == "secret":
+			tag.Secret = true
+		case opt == "file":
+			tag.File = true
+		case opt == "trim":
+			tag.Trim = true
+		case strings.HasPrefix(opt, "sep=") && len(opt) > 4:
+			tag.Separator = opt[4:]
+		case strings.HasPrefix(opt, "kvsep=") && len(opt) > 6:
+			tag.KeyValueSeparator = opt[6:]
+		case strings.HasPrefix(opt, "conv=") && len(opt) > 5:
+			tag.Converter = opt[5:]
+		case strings.HasPrefix(opt, "disc=") && len(opt) > 5:
+			tag.Discriminator = opt[5:]
+		case strings.HasPrefix(opt, "encoding=") && isEncoding(opt[9:]):
+			tag.Encoding = opt[9:]
+		case strings.HasPrefix(opt, "alias=") && len(opt) > 6:
+			tag.Aliases = append(tag.Aliases, opt[6:])
+		case strings.HasPrefix(opt, "deprecated=") && len(opt) > 11:
+			tag.Deprecated = append(tag.Deprecated, opt[11:])
 		default:
 			// invalid options increment the index position,
 			// so we end up with a slice of invalid options
@@ -120,3 +148,7 @@ func parse(tag *Tag, str string) error {
 	}
 	return nil
 }
+
+func isEncoding(str string) bool {
+	return str == EncodingBase64 || str == EncodingBase64URL || str == EncodingHex
+}
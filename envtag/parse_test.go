@@ -17,14 +17,52 @@ func TestParseTag(t *testing.T) {
 		wantTag Tag
 		wantErr error
 	}{
-		"":             {wantTag: Tag{}},
-		"-":            {wantTag: Tag{Ignore: true}},
-		"-,inline":     {wantTag: Tag{Ignore: true}},
-		"foo":          {wantTag: Tag{Name: "foo"}},
-		"foo,inline":   {wantTag: Tag{Name: "foo", Inline: true}},
-		"foo,include,": {wantTag: Tag{Name: "foo", Include: true}},
-		"FOOBAR":       {wantTag: Tag{Name: "FOOBAR"}},
-		",inline":      {wantTag: Tag{Inline: true}},
+		"":               {wantTag: Tag{}},
+		"-":              {wantTag: Tag{Ignore: true}},
+		"-,inline":       {wantTag: Tag{Ignore: true}},
+		"foo":            {wantTag: Tag{Name: "foo"}},
+		"foo,inline":     {wantTag: Tag{Name: "foo", Inline: true}},
+		"foo,include,":   {wantTag: Tag{Name: "foo", Include: true}},
+		"FOOBAR":         {wantTag: Tag{Name: "FOOBAR"}},
+		"foo,required":   {wantTag: Tag{Name: "foo", Required: true}},
+		"foo,allowempty": {wantTag: Tag{Name: "foo", AllowEmpty: true}},
+		"foo,secret":     {wantTag: Tag{Name: "foo", Secret: true}},
+		"foo,file,trim":  {wantTag: Tag{Name: "foo", File: true, Trim: true}},
+		"foo,conv=upper": {wantTag: Tag{Name: "foo", Converter: "upper"}},
+		"foo,disc=type":  {wantTag: Tag{Name: "foo", Discriminator: "type"}},
+		"foo,encoding=hex": {
+			wantTag: Tag{Name: "foo", Encoding: EncodingHex},
+		},
+		"foo,encoding=base64url": {
+			wantTag: Tag{Name: "foo", Encoding: EncodingBase64URL},
+		},
+		"foo,sep=;": {wantTag: Tag{Name: "foo", Separator: ";"}},
+		"foo,sep=|,kvsep=:": {
+			wantTag: Tag{Name: "foo", Separator: "|", KeyValueSeparator: ":"},
+		},
+		"foo,alias=BAR,alias=BAZ": {
+			wantTag: Tag{Name: "foo", Aliases: []string{"BAR", "BAZ"}},
+		},
+		"foo,deprecated=OLD_FOO,alias=BAR": {
+			wantTag: Tag{Name: "foo", Aliases: []string{"BAR"}, Deprecated: []string{"OLD_FOO"}},
+		},
+		"foo,sep=": {
+			wantTag: Tag{Name: "foo"},
+			wantErr: &Error{
+				TagString:   "foo,sep=",
+				Unsupported: []string{"sep="},
+			},
+		},
+		"foo,encoding=base32": {
+			wantTag: Tag{Name: "foo"},
+			wantErr: &Error{
+				TagString:   "foo,encoding=base32",
+				Unsupported: []string{"encoding=base32"},
+			},
+		},
+		",inline":  {wantTag: Tag{Inline: true}},
+		",sep=;":   {wantTag: Tag{Separator: ";"}},
+		",kvsep=:": {wantTag: Tag{KeyValueSeparator: ":"}},
 
 		",inline,invalid": {
 			wantTag: Tag{Inline: true},
@@ -53,6 +91,14 @@ func TestParseTag(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("separators only", func(t *testing.T) {
+		for _, tag := range []string{",sep=;", ",kvsep=:"} {
+			have, err := ParseTag(tag)
+			assert.NoError(t, err)
+			assert.False(t, have.IsEmpty(), tag)
+		}
+	})
 }
 
 func TestParseStructField(t *testing.T) {
@@ -62,6 +108,9 @@ func TestParseStructField(t *testing.T) {
 	type fixtureNamed struct {
 		Foo string `env:"BAR"`
 	}
+	type fixtureValidate struct {
+		Foo int `default:"1" validate:"min=1,max=10"`
+	}
 	type fixtureIgnore struct {
 		Foo string `env:"-" default:"some value"`
 	}
@@ -99,6 +148,10 @@ func TestParseStructField(t *testing.T) {
 			prefix: "PREFIX",
 			want:   Tag{Name: "BAR"},
 		},
+		"validate": {
+			field: reflect.TypeOf(fixtureValidate{}).Field(0),
+			want:  Tag{Name: "FOO", Default: "1", Validate: "min=1,max=10"},
+		},
 		"ignore": {
 			field: reflect.TypeOf(fixtureIgnore{}).Field(0),
 			want:  Tag{Ignore: true},
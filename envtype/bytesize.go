@@ -0,0 +1,123 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidByteSize errors.Msg = "invalid byte size"
+
+var (
+	_ env.Marshaler   = ByteSize(0)
+	_ env.Unmarshaler = (*ByteSize)(nil)
+)
+
+// ByteSize is a size in bytes. It is written with a decimal or binary unit,
+// e.g. 10MB or 512MiB.
+type ByteSize uint64
+
+const (
+	Byte ByteSize = 1
+
+	KB = 1000 * Byte
+	MB = 1000 * KB
+	GB = 1000 * MB
+	TB = 1000 * GB
+	PB = 1000 * TB
+
+	KiB = 1024 * Byte
+	MiB = 1024 * KiB
+	GiB = 1024 * MiB
+	TiB = 1024 * GiB
+	PiB = 1024 * TiB
+)
+
+type byteUnit struct {
+	name string
+	size ByteSize
+}
+
+// byteUnits are ordered from largest to smallest, binary before decimal
+// units, which is the order in which they are tried when formatting.
+var byteUnits = []byteUnit{
+	{"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	{"PB", PB}, {"TB", TB}, {"GB", GB}, {"MB", MB}, {"KB", KB},
+	{"B", Byte},
+}
+
+// ParseByteSize parses str, which is a (decimal) number with an optional
+// case-insensitive unit, e.g. 1024, 1.5GB or 512MiB. Without a unit the
+// number is in bytes.
+func ParseByteSize(str string) (ByteSize, error) {
+	str = strings.TrimSpace(str)
+	num, unit := str, ""
+	if i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	}); i >= 0 {
+		num, unit = str[:i], strings.TrimSpace(str[i:])
+	}
+
+	size := Byte
+	if unit != "" {
+		var found bool
+		for _, u := range byteUnits {
+			if strings.EqualFold(unit, u.name) {
+				size, found = u.size, true
+				break
+			}
+		}
+		if !found {
+			return 0, errors.New(ErrInvalidByteSize)
+		}
+	}
+
+	if !strings.ContainsRune(num, '.') {
+		n, err := strconv.ParseUint(num, 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, ErrInvalidByteSize)
+		}
+		if n > math.MaxUint64/uint64(size) {
+			return 0, errors.New(ErrInvalidByteSize)
+		}
+		return ByteSize(n) * size, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, ErrInvalidByteSize)
+	}
+	if f *= float64(size); f >= math.MaxUint64 {
+		return 0, errors.New(ErrInvalidByteSize)
+	}
+	return ByteSize(f), nil
+}
+
+// String returns the size with the largest unit it is a whole multiple of,
+// e.g. 512MiB.
+func (b ByteSize) String() string {
+	if b == 0 {
+		return "0B"
+	}
+	for _, u := range byteUnits {
+		if b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.name
+		}
+	}
+	// unreachable, every size is a multiple of Byte
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+func (b ByteSize) MarshalEnv() ([]byte, error) { return []byte(b.String()), nil }
+
+func (b *ByteSize) UnmarshalEnv(data []byte) (err error) {
+	*b, err = ParseByteSize(string(data))
+	return err
+}
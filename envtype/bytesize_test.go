@@ -0,0 +1,60 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]ByteSize{
+		"0":        0,
+		"1024":     KiB,
+		"512MiB":   512 * MiB,
+		"512 mib":  512 * MiB,
+		"10MB":     10 * MB,
+		"1.5GB":    1500 * MB,
+		"1.5GiB":   1536 * MiB,
+		"2tib":     2 * TiB,
+		"100B":     100,
+		" 16 KiB ": 16 * KiB,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have, err := ParseByteSize(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", "MiB", "10XB", "1..5MB", "-1KB", "99999999PiB"} {
+			_, err := ParseByteSize(input)
+			assert.ErrorIs(t, err, ErrInvalidByteSize, input)
+		}
+	})
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := map[ByteSize]string{
+		0:           "0B",
+		100:         "100B",
+		KiB:         "1KiB",
+		512 * MiB:   "512MiB",
+		1536 * MiB:  "1536MiB",
+		10 * MB:     "10MB",
+		1500 * MB:   "1500MB",
+		1025:        "1025B",
+		4 * TiB:     "4TiB",
+		2000 * 1000: "2MB",
+	}
+	for input, want := range tests {
+		t.Run(want, func(t *testing.T) {
+			assert.Equal(t, want, input.String())
+		})
+	}
+}
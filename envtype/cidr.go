@@ -0,0 +1,96 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"net"
+	"strings"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ env.Marshaler   = CIDR{}
+	_ env.Unmarshaler = (*CIDR)(nil)
+	_ env.Marshaler   = CIDRList{}
+	_ env.Unmarshaler = (*CIDRList)(nil)
+)
+
+// CIDR is an IP network in CIDR notation, e.g. 10.0.0.0/8.
+type CIDR struct{ net.IPNet }
+
+// ParseCIDR parses str as an IP network in CIDR notation.
+func ParseCIDR(str string) (CIDR, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(str))
+	if err != nil {
+		return CIDR{}, errors.WithStack(err)
+	}
+	return CIDR{IPNet: *ipNet}, nil
+}
+
+// String returns the network in CIDR notation, or an empty string when c is
+// zero.
+func (c CIDR) String() string {
+	if c.IP == nil {
+		return ""
+	}
+	return c.IPNet.String()
+}
+
+func (c CIDR) MarshalEnv() ([]byte, error) { return []byte(c.String()), nil }
+
+func (c *CIDR) UnmarshalEnv(data []byte) (err error) {
+	*c, err = ParseCIDR(string(data))
+	return err
+}
+
+// CIDRList is a comma separated list of IP networks in CIDR notation, e.g.
+// 10.0.0.0/8,192.168.0.0/16.
+type CIDRList []CIDR
+
+// ParseCIDRList parses str as a comma separated list of IP networks in CIDR
+// notation.
+func ParseCIDRList(str string) (CIDRList, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(str, ",")
+	res := make(CIDRList, 0, len(parts))
+	for _, part := range parts {
+		c, err := ParseCIDR(part)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// Contains reports whether any of the networks contains ip.
+func (l CIDRList) Contains(ip net.IP) bool {
+	for _, c := range l {
+		if c.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l CIDRList) String() string {
+	parts := make([]string, len(l))
+	for i, c := range l {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l CIDRList) MarshalEnv() ([]byte, error) { return []byte(l.String()), nil }
+
+func (l *CIDRList) UnmarshalEnv(data []byte) (err error) {
+	*l, err = ParseCIDRList(string(data))
+	return err
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCIDR(t *testing.T) {
+	have, err := ParseCIDR("192.168.1.10/24")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.0/24", have.String())
+	assert.True(t, have.Contains(net.ParseIP("192.168.1.99")))
+
+	_, err = ParseCIDR("192.168.1.10")
+	assert.Error(t, err)
+
+	assert.Equal(t, "", CIDR{}.String())
+}
+
+func TestParseCIDRList(t *testing.T) {
+	have, err := ParseCIDRList("10.0.0.0/8, 192.168.0.0/16,fd00::/8")
+	assert.NoError(t, err)
+	assert.Len(t, have, 3)
+	assert.Equal(t, "10.0.0.0/8,192.168.0.0/16,fd00::/8", have.String())
+
+	assert.True(t, have.Contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, have.Contains(net.ParseIP("fd00::1")))
+	assert.False(t, have.Contains(net.ParseIP("172.16.0.1")))
+
+	t.Run("empty", func(t *testing.T) {
+		have, err := ParseCIDRList(" ")
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseCIDRList("10.0.0.0/8,foo")
+		assert.Error(t, err)
+	})
+}
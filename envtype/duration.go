@@ -0,0 +1,98 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidDuration errors.Msg = "invalid duration"
+
+var (
+	_ env.Marshaler   = Duration(0)
+	_ env.Unmarshaler = (*Duration)(nil)
+)
+
+// Day is a duration of 24 hours.
+const Day = 24 * time.Hour
+
+// Duration is a [time.Duration] which supports days as a unit, e.g. 7d or
+// 1d12h.
+type Duration time.Duration
+
+// ParseDuration parses str similar to [time.ParseDuration], with an optional
+// leading number of days, e.g. 30d, 1.5d or -1d12h30m.
+func ParseDuration(str string) (Duration, error) {
+	str = strings.TrimSpace(str)
+	rest, sign := str, 1.0
+	if rest != "" && (rest[0] == '-' || rest[0] == '+') {
+		if rest[0] == '-' {
+			sign = -1
+		}
+		rest = rest[1:]
+	}
+
+	var days float64
+	if i := strings.IndexByte(rest, 'd'); i >= 0 {
+		var err error
+		if days, err = strconv.ParseFloat(rest[:i], 64); err != nil || days < 0 {
+			return 0, errors.New(ErrInvalidDuration)
+		}
+		if rest = rest[i+1:]; rest == "" {
+			rest = "0"
+		}
+	}
+
+	if strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		// only a single leading sign is allowed
+		return 0, errors.New(ErrInvalidDuration)
+	}
+	d, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, errors.Wrap(err, ErrInvalidDuration)
+	}
+
+	res := days*float64(Day) + float64(d)
+	if res > math.MaxInt64 {
+		return 0, errors.New(ErrInvalidDuration)
+	}
+	return Duration(sign * res), nil
+}
+
+// Duration returns d as [time.Duration].
+func (d Duration) Duration() time.Duration { return time.Duration(d) }
+
+// String returns d in the format of [time.Duration.String], with a leading
+// number of days when d is at least a day, e.g. 7d or 1d12h0m0s.
+func (d Duration) String() string {
+	td := time.Duration(d)
+	var sign string
+	if td < 0 {
+		sign, td = "-", -td
+	}
+
+	days, rest := td/Day, td%Day
+	switch {
+	case days == 0:
+		return sign + rest.String()
+	case rest == 0:
+		return sign + strconv.FormatInt(int64(days), 10) + "d"
+	default:
+		return sign + strconv.FormatInt(int64(days), 10) + "d" + rest.String()
+	}
+}
+
+func (d Duration) MarshalEnv() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Duration) UnmarshalEnv(data []byte) (err error) {
+	*d, err = ParseDuration(string(data))
+	return err
+}
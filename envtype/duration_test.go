@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"0":         0,
+		"5s":        5 * time.Second,
+		"1h30m":     90 * time.Minute,
+		"7d":        7 * Day,
+		"1.5d":      36 * time.Hour,
+		"1d12h":     36 * time.Hour,
+		"-1d12h30m": -(Day + 12*time.Hour + 30*time.Minute),
+		"+2d":       2 * Day,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have, err := ParseDuration(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have.Duration())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", "d", "1x", "12h1d", "1d-2h", "--1d", "999999999d"} {
+			_, err := ParseDuration(input)
+			assert.ErrorIs(t, err, ErrInvalidDuration, input)
+		}
+	})
+}
+
+func TestDuration_String(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                          "0s",
+		5 * time.Second:            "5s",
+		7 * Day:                    "7d",
+		36 * time.Hour:             "1d12h0m0s",
+		-(Day + 90*time.Second):    "-1d1m30s",
+		23*time.Hour + time.Minute: "23h1m0s",
+	}
+	for input, want := range tests {
+		t.Run(want, func(t *testing.T) {
+			assert.Equal(t, want, Duration(input).String())
+
+			have, err := ParseDuration(want)
+			assert.NoError(t, err)
+			assert.Equal(t, Duration(input), have)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package envtype provides types for common configuration values. Each type
+// implements [env.Marshaler] and [env.Unmarshaler], so [env.Decoder],
+// [env.Encoder] and [env.Format] handle them natively.
+//
+//	type Config struct {
+//		MaxUpload envtype.ByteSize `default:"512MiB"`
+//		Retention envtype.Duration `default:"30d"`
+//		Listen    envtype.HostPort `default:":8080"`
+//	}
+//
+// Fields of type [time.Time] or [Time] can be (un)marshaled using a specific
+// layout with the conv tag option, e.g. `env:"START,conv=date"`, after
+// registering the layout using [RegisterTimeLayout], or the common layouts
+// using [RegisterTimeLayouts].
+package envtype
@@ -0,0 +1,86 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() { RegisterTimeLayouts() }
+
+type config struct {
+	MaxUpload ByteSize  `default:"512MiB"`
+	Retention Duration  `default:"30d"`
+	Endpoint  HTTPURL   `default:"https://example.com"`
+	Listen    HostPort  `default:":8080"`
+	Trusted   CIDRList  `default:"10.0.0.0/8,192.168.0.0/16"`
+	Mode      FileMode  `default:"0640"`
+	Zone      Location  `default:"UTC"`
+	Pattern   Regexp    `default:"^[a-z]+$"`
+	Start     time.Time `env:",conv=date" default:"2025-01-02"`
+	Created   Time
+}
+
+func TestDecoder(t *testing.T) {
+	var have config
+	assert.NoError(t, env.NewDecoder(env.Map{
+		"LISTEN":  "localhost:9090",
+		"CREATED": "2025-01-02T15:04:05Z",
+	}).Decode(&have))
+
+	assert.Equal(t, 512*MiB, have.MaxUpload)
+	assert.Equal(t, 30*Day, have.Retention.Duration())
+	assert.Equal(t, "example.com", have.Endpoint.Host)
+	assert.Equal(t, HostPort{Host: "localhost", Port: 9090}, have.Listen)
+	assert.Len(t, have.Trusted, 2)
+	assert.Equal(t, FileMode(0o640), have.Mode)
+	assert.Equal(t, time.UTC, have.Zone.Location)
+	assert.True(t, have.Pattern.MatchString("foo"))
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), have.Start)
+	assert.Equal(t, time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC), have.Created.Time)
+
+	t.Run("invalid", func(t *testing.T) {
+		var have config
+		err := env.NewDecoder(env.Map{"MAX_UPLOAD": "lots"}).Decode(&have)
+
+		var decErr *env.DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.Equal(t, "MAX_UPLOAD", decErr.Key)
+		assert.ErrorIs(t, err, ErrInvalidByteSize)
+	})
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, env.NewEncoder(&buf).
+		WithOptions(env.EncodeOptions{TakeValues: true}).
+		Encode(config{
+			Listen:  HostPort{Host: "localhost", Port: 9090},
+			Created: Time{Time: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)},
+		}),
+	)
+	assert.Equal(t, `MAX_UPLOAD=512MiB
+RETENTION=30d
+ENDPOINT=https://example.com
+LISTEN=localhost:9090
+TRUSTED=10.0.0.0/8,192.168.0.0/16
+MODE=0640
+ZONE=UTC
+PATTERN=^[a-z]+$
+START=2025-01-02
+CREATED=2025-01-02T15:04:05Z
+`, buf.String())
+}
+
+func TestFormat(t *testing.T) {
+	have, err := env.Format("SIZE", 10*GiB)
+	assert.NoError(t, err)
+	assert.Equal(t, "SIZE=10GiB", have)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ env.Marshaler   = FileMode(0)
+	_ env.Unmarshaler = (*FileMode)(nil)
+)
+
+// FileMode is an [fs.FileMode] which is written as an octal number, e.g.
+// 0644.
+type FileMode fs.FileMode
+
+// ParseFileMode parses str as an octal number, with an optional 0 or 0o
+// prefix, e.g. 0644, 0o755 or 600.
+func ParseFileMode(str string) (FileMode, error) {
+	str = strings.TrimSpace(str)
+	if len(str) > 1 && (str[1] == 'o' || str[1] == 'O') && str[0] == '0' {
+		str = str[2:]
+	}
+
+	m, err := strconv.ParseUint(str, 8, 32)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return FileMode(m), nil
+}
+
+// FileMode returns m as [fs.FileMode].
+func (m FileMode) FileMode() fs.FileMode { return fs.FileMode(m) }
+
+// String returns m as an octal number with a leading 0, e.g. 0644.
+func (m FileMode) String() string {
+	if m == 0 {
+		return "0"
+	}
+	return "0" + strconv.FormatUint(uint64(m), 8)
+}
+
+func (m FileMode) MarshalEnv() ([]byte, error) { return []byte(m.String()), nil }
+
+func (m *FileMode) UnmarshalEnv(data []byte) (err error) {
+	*m, err = ParseFileMode(string(data))
+	return err
+}
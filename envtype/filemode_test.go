@@ -0,0 +1,40 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFileMode(t *testing.T) {
+	tests := map[string]fs.FileMode{
+		"0644":  0o644,
+		"0o755": 0o755,
+		"600":   0o600,
+		"0":     0,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have, err := ParseFileMode(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have.FileMode())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", "0o", "0888", "rw-r--r--"} {
+			_, err := ParseFileMode(input)
+			assert.Error(t, err, input)
+		}
+	})
+}
+
+func TestFileMode_String(t *testing.T) {
+	assert.Equal(t, "0644", FileMode(0o644).String())
+	assert.Equal(t, "0", FileMode(0).String())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ env.Marshaler   = HostPort{}
+	_ env.Unmarshaler = (*HostPort)(nil)
+)
+
+// HostPort is a network address in the form of host:port. The host may be
+// empty, e.g. :8080.
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// ParseHostPort parses str in the form of host:port, [host]:port or :port.
+func ParseHostPort(str string) (HostPort, error) {
+	host, port, err := net.SplitHostPort(str)
+	if err != nil {
+		return HostPort{}, errors.WithStack(err)
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, errors.WithStack(err)
+	}
+	return HostPort{Host: host, Port: uint16(p)}, nil
+}
+
+// String returns the address in the form of host:port, or an empty string
+// when hp is zero.
+func (hp HostPort) String() string {
+	if hp == (HostPort{}) {
+		return ""
+	}
+	return net.JoinHostPort(hp.Host, strconv.FormatUint(uint64(hp.Port), 10))
+}
+
+func (hp HostPort) MarshalEnv() ([]byte, error) { return []byte(hp.String()), nil }
+
+func (hp *HostPort) UnmarshalEnv(data []byte) (err error) {
+	*hp, err = ParseHostPort(string(data))
+	return err
+}
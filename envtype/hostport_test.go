@@ -0,0 +1,37 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostPort(t *testing.T) {
+	tests := map[string]HostPort{
+		"localhost:8080": {Host: "localhost", Port: 8080},
+		":80":            {Port: 80},
+		"[::1]:443":      {Host: "::1", Port: 443},
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have, err := ParseHostPort(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+			assert.Equal(t, input, have.String())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"localhost", "localhost:", "localhost:http", ":65536"} {
+			_, err := ParseHostPort(input)
+			assert.Error(t, err, input)
+		}
+	})
+	t.Run("zero", func(t *testing.T) {
+		assert.Equal(t, "", HostPort{}.String())
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package envtype
+
+import (
+	"log/slog"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ env.Marshaler   = Level(0)
+	_ env.Unmarshaler = (*Level)(nil)
+)
+
+// Level is a [slog.Level], which is written as its case-insensitive name with
+// an optional offset, e.g. debug, WARN or INFO+2.
+type Level slog.Level
+
+// Level returns l as [slog.Level]. It implements [slog.Leveler].
+func (l Level) Level() slog.Level { return slog.Level(l) }
+
+func (l Level) String() string { return slog.Level(l).String() }
+
+func (l Level) MarshalEnv() ([]byte, error) { return []byte(l.String()), nil }
+
+func (l *Level) UnmarshalEnv(data []byte) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText(data); err != nil {
+		return errors.WithStack(err)
+	}
+	*l = Level(lvl)
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package envtype
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevel_UnmarshalEnv(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug":  slog.LevelDebug,
+		"INFO":   slog.LevelInfo,
+		"Warn":   slog.LevelWarn,
+		"ERROR":  slog.LevelError,
+		"INFO+2": slog.LevelInfo + 2,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			var have Level
+			assert.NoError(t, have.UnmarshalEnv([]byte(input)))
+			assert.Equal(t, want, have.Level())
+		})
+	}
+
+	var have Level
+	assert.Error(t, have.UnmarshalEnv([]byte("verbose")))
+}
+
+func TestLevel_MarshalEnv(t *testing.T) {
+	b, err := Level(slog.LevelWarn).MarshalEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "WARN", string(b))
+}
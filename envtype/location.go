@@ -0,0 +1,45 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"time"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ env.Marshaler   = Location{}
+	_ env.Unmarshaler = (*Location)(nil)
+)
+
+// Location is a [time.Location], which is written as its IANA Time Zone
+// database name, e.g. Europe/Amsterdam, or UTC or Local.
+type Location struct{ *time.Location }
+
+// LoadLocation loads the [Location] with name using [time.LoadLocation].
+func LoadLocation(name string) (Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return Location{}, errors.WithStack(err)
+	}
+	return Location{Location: loc}, nil
+}
+
+// String returns the name of the location, or an empty string when it is nil.
+func (l Location) String() string {
+	if l.Location == nil {
+		return ""
+	}
+	return l.Location.String()
+}
+
+func (l Location) MarshalEnv() ([]byte, error) { return []byte(l.String()), nil }
+
+func (l *Location) UnmarshalEnv(data []byte) (err error) {
+	*l, err = LoadLocation(string(data))
+	return err
+}
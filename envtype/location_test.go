@@ -0,0 +1,24 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLocation(t *testing.T) {
+	have, err := LoadLocation("UTC")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, have.Location)
+	assert.Equal(t, "UTC", have.String())
+
+	_, err = LoadLocation("Nowhere/Special")
+	assert.Error(t, err)
+
+	assert.Equal(t, "", Location{}.String())
+}
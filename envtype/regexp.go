@@ -0,0 +1,40 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"regexp"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+var (
+	_ env.Marshaler   = Regexp{}
+	_ env.Unmarshaler = (*Regexp)(nil)
+)
+
+// Regexp is a compiled [regexp.Regexp].
+type Regexp struct{ *regexp.Regexp }
+
+// String returns the source text of the regular expression, or an empty
+// string when it is nil.
+func (r Regexp) String() string {
+	if r.Regexp == nil {
+		return ""
+	}
+	return r.Regexp.String()
+}
+
+func (r Regexp) MarshalEnv() ([]byte, error) { return []byte(r.String()), nil }
+
+func (r *Regexp) UnmarshalEnv(data []byte) error {
+	re, err := regexp.Compile(string(data))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Regexp = re
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexp_UnmarshalEnv(t *testing.T) {
+	var have Regexp
+	assert.NoError(t, have.UnmarshalEnv([]byte("^v[0-9]+$")))
+	assert.True(t, have.MatchString("v12"))
+	assert.Equal(t, "^v[0-9]+$", have.String())
+
+	assert.Error(t, have.UnmarshalEnv([]byte("[a-")))
+	assert.Equal(t, "", Regexp{}.String())
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"time"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+const ErrTimeExpected errors.Msg = "expected a time.Time"
+
+var (
+	_ env.Marshaler   = Time{}
+	_ env.Unmarshaler = (*Time)(nil)
+)
+
+// Time is a [time.Time] which is written in the [time.RFC3339] layout by
+// default. Fields of type [Time] or [time.Time] can use another layout by
+// referring to a registered layout with the conv tag option, e.g.
+// `env:"START,conv=date"`.
+type Time struct{ time.Time }
+
+func (t Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (t Time) MarshalEnv() ([]byte, error) { return []byte(t.String()), nil }
+
+func (t *Time) UnmarshalEnv(data []byte) error {
+	tt, err := time.Parse(time.RFC3339, string(data))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	t.Time = tt
+	return nil
+}
+
+// RegisterTimeLayouts registers the following common layouts as
+// [env.Converter]s with the package-global [env.Registry], using
+// [RegisterTimeLayout]: rfc3339, rfc3339nano, rfc1123, rfc1123z, rfc822,
+// rfc822z, datetime, date, time and kitchen. It is not called by this
+// package, call it once before decoding or encoding to use the layouts.
+func RegisterTimeLayouts() {
+	RegisterTimeLayout("rfc3339", time.RFC3339)
+	RegisterTimeLayout("rfc3339nano", time.RFC3339Nano)
+	RegisterTimeLayout("rfc1123", time.RFC1123)
+	RegisterTimeLayout("rfc1123z", time.RFC1123Z)
+	RegisterTimeLayout("rfc822", time.RFC822)
+	RegisterTimeLayout("rfc822z", time.RFC822Z)
+	RegisterTimeLayout("datetime", time.DateTime)
+	RegisterTimeLayout("date", time.DateOnly)
+	RegisterTimeLayout("time", time.TimeOnly)
+	RegisterTimeLayout("kitchen", time.Kitchen)
+}
+
+// RegisterTimeLayout registers a [env.Converter] with name, which
+// (un)marshals fields of type [Time] or [time.Time] using layout, with the
+// package-global [env.Registry]. Fields refer to it using the conv tag
+// option, e.g. `env:"START,conv=name"`. Use [env.Registry.RegisterConverter]
+// together with [TimeConverter] to register a layout with another Registry.
+func RegisterTimeLayout(name, layout string) {
+	env.RegisterConverter(name, TimeConverter(layout))
+}
+
+// TimeConverter returns a [env.Converter] which (un)marshals values of type
+// [Time] or [time.Time] using layout.
+func TimeConverter(layout string) env.Converter {
+	return env.Converter{
+		Marshal: func(v any) (string, error) {
+			switch t := v.(type) {
+			case time.Time:
+				return formatTime(t, layout), nil
+			case Time:
+				return formatTime(t.Time, layout), nil
+			default:
+				return "", errors.New(ErrTimeExpected)
+			}
+		},
+		Unmarshal: func(val rawconv.Value, dest any) error {
+			tt, err := time.Parse(layout, val.String())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			switch t := dest.(type) {
+			case *time.Time:
+				*t = tt
+			case *Time:
+				t.Time = tt
+			default:
+				return errors.New(ErrTimeExpected)
+			}
+			return nil
+		},
+	}
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTime_UnmarshalEnv(t *testing.T) {
+	var have Time
+	assert.NoError(t, have.UnmarshalEnv([]byte("2025-01-02T15:04:05Z")))
+	assert.Equal(t, time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC), have.Time)
+	assert.Equal(t, "2025-01-02T15:04:05Z", have.String())
+
+	assert.Error(t, have.UnmarshalEnv([]byte("2025-01-02")))
+	assert.Equal(t, "", Time{}.String())
+}
+
+func TestTimeConverter(t *testing.T) {
+	conv := TimeConverter(time.DateOnly)
+	want := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var tt time.Time
+	assert.NoError(t, conv.Unmarshal(rawconv.Value("2025-01-02"), &tt))
+	assert.Equal(t, want, tt)
+
+	var et Time
+	assert.NoError(t, conv.Unmarshal(rawconv.Value("2025-01-02"), &et))
+	assert.Equal(t, want, et.Time)
+
+	str, err := conv.Marshal(want)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-01-02", str)
+
+	str, err = conv.Marshal(Time{Time: want})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-01-02", str)
+
+	var s string
+	assert.ErrorIs(t, conv.Unmarshal(rawconv.Value("2025-01-02"), &s), ErrTimeExpected)
+	_, err = conv.Marshal(s)
+	assert.ErrorIs(t, err, ErrTimeExpected)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/go-pogo/env"
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrMissingScheme errors.Msg = "url is missing a scheme"
+	ErrInvalidScheme errors.Msg = "url has an invalid scheme"
+)
+
+var (
+	_ env.Marshaler   = URL{}
+	_ env.Unmarshaler = (*URL)(nil)
+	_ env.Marshaler   = HTTPURL{}
+	_ env.Unmarshaler = (*HTTPURL)(nil)
+)
+
+// URL is an absolute [url.URL], which must have a scheme.
+type URL struct{ url.URL }
+
+// ParseURL parses str, with any surrounding whitespace trimmed, into an
+// absolute [URL]. When schemes are provided, the scheme of the URL must be one
+// of them.
+func ParseURL(str string, schemes ...string) (URL, error) {
+	u, err := url.Parse(strings.TrimSpace(str))
+	if err != nil {
+		return URL{}, errors.WithStack(err)
+	}
+	if u.Scheme == "" {
+		return URL{}, errors.New(ErrMissingScheme)
+	}
+	if len(schemes) == 0 {
+		return URL{URL: *u}, nil
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return URL{URL: *u}, nil
+		}
+	}
+	return URL{}, errors.Wrap(ErrInvalidScheme, u.Scheme)
+}
+
+func (u URL) String() string { return u.URL.String() }
+
+func (u URL) MarshalEnv() ([]byte, error) { return []byte(u.String()), nil }
+
+func (u *URL) UnmarshalEnv(data []byte) (err error) {
+	*u, err = ParseURL(string(data))
+	return err
+}
+
+// HTTPURL is a [URL] with scheme http or https.
+type HTTPURL struct{ URL }
+
+func (u HTTPURL) MarshalEnv() ([]byte, error) { return []byte(u.String()), nil }
+
+func (u *HTTPURL) UnmarshalEnv(data []byte) (err error) {
+	u.URL, err = ParseURL(string(data), "http", "https")
+	return err
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package envtype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseURL(t *testing.T) {
+	have, err := ParseURL("https://example.com/path?q=1")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", have.Host)
+	assert.Equal(t, "https://example.com/path?q=1", have.String())
+
+	have, err = ParseURL(" https://example.com\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", have.String())
+
+	_, err = ParseURL("example.com/path")
+	assert.ErrorIs(t, err, ErrMissingScheme)
+
+	_, err = ParseURL("ftp://example.com", "http", "https")
+	assert.ErrorIs(t, err, ErrInvalidScheme)
+}
+
+func TestHTTPURL_UnmarshalEnv(t *testing.T) {
+	var have HTTPURL
+	assert.NoError(t, have.UnmarshalEnv([]byte("http://localhost:8080")))
+	assert.Equal(t, "localhost:8080", have.Host)
+
+	b, err := have.MarshalEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", string(b))
+
+	assert.ErrorIs(t, have.UnmarshalEnv([]byte("redis://localhost")), ErrInvalidScheme)
+}
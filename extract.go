@@ -20,6 +20,8 @@ func Extract(v any) (map[string]any, error) {
 // value.
 type Extractor struct {
 	TagOptions
+
+	registry *Registry
 }
 
 // NewExtractor returns a new [Extractor].
@@ -35,7 +37,20 @@ func (ex *Extractor) WithTagOptions(opts TagOptions) *Extractor {
 	return ex
 }
 
+// WithRegistry sets the [Registry] which is used to parse default values. It
+// defaults to the package-global [Registry].
+func (ex *Extractor) WithRegistry(r *Registry) *Extractor {
+	if r == nil {
+		panic(panicNilRegistry)
+	}
+
+	ex.registry = r
+	return ex
+}
+
 // Extract environment variables names and values from the provided struct v.
+// The values of fields that are secrets are returned as [Secret], so they are
+// redacted when formatted.
 func (ex *Extractor) Extract(v any) (map[string]any, error) {
 	rv, ok := v.(reflect.Value)
 	if !ok {
@@ -45,15 +60,35 @@ func (ex *Extractor) Extract(v any) (map[string]any, error) {
 		return nil, errors.New(ErrStructExpected)
 	}
 
+	reg := registryOrGlobal(ex.registry)
 	res := make(map[string]any)
 	trav := &traverser{
-		TagOptions:  ex.TagOptions,
-		isKnownType: typeKnownByUnmarshaler,
-		handleField: func(rv reflect.Value, tag envtag.Tag) (err error) {
+		TagOptions:   ex.TagOptions,
+		isKnownType:  reg.knownByUnmarshaler,
+		prepareSlice: valueSlice,
+		prepareMap:   reg.valueMap,
+		prepareIface: func(rv reflect.Value, field fieldInfo) (reflect.Value, error) {
+			el, name, err := reg.variantValue(rv, field)
+			if err == nil && el.IsValid() {
+				res[discriminatorKey(field.Tag)] = name
+			}
+			return el, err
+		},
+		handleField: func(rv reflect.Value, field fieldInfo) (err error) {
+			tag := field.Tag
 			if rv.IsZero() && tag.Default != "" {
-				if rv, err = defaultValue(rv.Type(), tag.DefaultValue()); err != nil {
+				if rv, err = reg.defaultValue(rv.Type(), tag); err != nil {
+					return err
+				}
+			}
+
+			if isSecret(rv.Type(), tag) && rv.Type() != secretType {
+				val, err := reg.marshalField(rv, tag)
+				if err != nil {
 					return err
 				}
+				res[tag.Name] = Secret(val)
+				return nil
 			}
 
 			res[tag.Name] = rv.Interface()
@@ -12,8 +12,35 @@ import (
 type Formatter func(name string, val any) (string, error)
 
 // Format the name and val using a standard env format and return the resulting
-// line as a string.
+// line as a string. The items of slices, arrays and maps are joined using the
+// default separators, use [FormatSeparators] to format them using other
+// separators. Byte slices are formatted as a list of numbers, encode them
+// using [EncodeBytes] to format them as base64 or hex.
+//
+//	val, err := env.EncodeBytes(key, envtag.EncodingBase64)
+//	line, err := env.Format("KEY", val)
+//
+// Values are always marshaled using the package-global [Registry]. An
+// [Encoder] with a Registry set using [Encoder.WithRegistry] marshals its
+// values before passing them to its [Formatter].
 func Format(name string, val any) (string, error) {
+	return format(name, val, newSeparators("", ""))
+}
+
+// FormatSeparators returns a [Formatter] which formats similar to [Format],
+// but joins the items of slices, arrays and maps using the provided items
+// separator sep and key-value separator kvsep. Empty separators default to
+// [DefaultItemsSeparator] and [DefaultKeyValueSeparator].
+//
+//	line, err := env.FormatSeparators(";", ":")("LABELS", labels)
+func FormatSeparators(sep, kvsep string) Formatter {
+	seps := newSeparators(sep, kvsep)
+	return func(name string, val any) (string, error) {
+		return format(name, val, seps)
+	}
+}
+
+func format(name string, val any, seps separators) (string, error) {
 	switch v := val.(type) {
 	case string:
 		return fmtStringValue(name, quote(v)), nil
@@ -22,10 +49,10 @@ func Format(name string, val any) (string, error) {
 		return fmtStringValue(name, quote(v.String())), nil
 
 	case reflect.Value:
-		return fmtReflectValue(name, v)
+		return fmtReflectValue(name, v, seps)
 
 	default:
-		return fmtReflectValue(name, reflect.ValueOf(v))
+		return fmtReflectValue(name, reflect.ValueOf(v), seps)
 	}
 }
 
@@ -39,14 +66,14 @@ func fmtStringValue(name, val string) string {
 	return name + "=" + val
 }
 
-func fmtReflectValue(name string, rv reflect.Value) (string, error) {
-	val, err := marshaler.Marshal(rv)
+func fmtReflectValue(name string, rv reflect.Value, seps separators) (string, error) {
+	val, err := global.marshalValue(rv, seps)
 	if err != nil {
 		return "", err
 	}
 
 	v := val.String()
-	if rv.Kind() == reflect.String {
+	if rv.Kind() == reflect.String || isListType(rv.Type(), global.knownByMarshaler) {
 		v = quote(v)
 	}
 	return fmtStringValue(name, v), nil
@@ -66,9 +93,10 @@ func quote(str string) string {
 	quot := "\""
 	if isq == -1 && idq >= 0 {
 		quot = "'"
-	} else if isq >= 0 && idq >= 0 {
-		str = strings.ReplaceAll(str, quot, "\\"+quot)
 	}
 
+	// backslashes are escape characters within quoted values, so they need
+	// to be escaped as well
+	str = strings.NewReplacer(`\`, `\\`, quot, `\`+quot).Replace(str)
 	return quot + str + quot
 }
@@ -0,0 +1,77 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+
+	"github.com/go-pogo/errors"
+)
+
+// Get looks up the environment variable named by the key from any of the
+// provided [Lookupper](s), or the system's environment variables when none
+// are provided. Its [Value] is unmarshaled into a value of type T using the
+// package-global [Registry]. Any error is returned as a *[DecodeError], which
+// contains the key and type T. When the key is not present the error
+// contains [ErrNotFound].
+//
+//	port, err := env.Get[uint16]("PORT")
+func Get[T any](key string, from ...Lookupper) (T, error) {
+	val, err := lookupOrSystem(key, from)
+	return getValue[T](key, val, err)
+}
+
+// GetOr is similar to [Get] but returns def when the environment variable
+// named by the key is not present or is empty. When its value cannot be
+// unmarshaled, def is returned together with the error.
+//
+//	host, err := env.GetOr("HOST", "localhost")
+func GetOr[T any](key string, def T, from ...Lookupper) (T, error) {
+	val, err := lookupOrSystem(key, from)
+	if IsNotFound(err) || (err == nil && val.IsEmpty()) {
+		return def, nil
+	}
+
+	res, err := getValue[T](key, val, err)
+	if err != nil {
+		return def, err
+	}
+	return res, nil
+}
+
+// MustGet is similar to [Get] but panics when an error occurs.
+func MustGet[T any](key string, from ...Lookupper) T {
+	res, err := Get[T](key, from...)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func lookupOrSystem(key string, from []Lookupper) (Value, error) {
+	if len(from) == 0 {
+		from = []Lookupper{System()}
+	}
+	return Lookup(key, from...)
+}
+
+// getValue unmarshals val into a value of type T, unless err is not nil. Any
+// error is returned as a *[DecodeError].
+func getValue[T any](key string, val Value, err error) (T, error) {
+	var res T
+	rv := reflect.ValueOf(&res).Elem()
+	if err == nil {
+		err = global.unmarshalValue(val, rv, newSeparators("", ""))
+	}
+	if err != nil {
+		return res, errors.WithStack(&DecodeError{
+			Key:   key,
+			Value: val,
+			Type:  rv.Type(),
+			Err:   err,
+		})
+	}
+	return res, nil
+}
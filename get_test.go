@@ -0,0 +1,97 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	src := Map{
+		"PORT":    "8080",
+		"TIMEOUT": "5s",
+		"HOSTS":   "a.local,b.local",
+		"INVALID": "foo",
+	}
+
+	t.Run("int", func(t *testing.T) {
+		have, err := Get[int]("PORT", src)
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, have)
+	})
+	t.Run("duration", func(t *testing.T) {
+		have, err := Get[time.Duration]("TIMEOUT", src)
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Second, have)
+	})
+	t.Run("slice", func(t *testing.T) {
+		have, err := Get[[]string]("HOSTS", src)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.local", "b.local"}, have)
+	})
+	t.Run("system", func(t *testing.T) {
+		t.Setenv("GET_TEST_BOOL", "true")
+		have, err := Get[bool]("GET_TEST_BOOL")
+		assert.NoError(t, err)
+		assert.True(t, have)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := Get[int]("NOPE", src)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.Equal(t, "NOPE", decErr.Key)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Get[int]("INVALID", src)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.Equal(t, "INVALID", decErr.Key)
+		assert.Equal(t, Value("foo"), decErr.Value)
+		assert.Equal(t, reflect.TypeOf(0), decErr.Type)
+		assert.ErrorContains(t, err, "`INVALID=foo` into (int)")
+	})
+}
+
+func TestGetOr(t *testing.T) {
+	src := Map{"PORT": "8080", "EMPTY": "", "INVALID": "80a"}
+
+	tests := map[string]int{
+		"PORT":  8080,
+		"NOPE":  80,
+		"EMPTY": 80,
+	}
+	for key, want := range tests {
+		t.Run(key, func(t *testing.T) {
+			have, err := GetOr(key, 80, src)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		have, err := GetOr("INVALID", 80, src)
+		assert.Equal(t, 80, have)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.Equal(t, "INVALID", decErr.Key)
+		assert.Equal(t, Value("80a"), decErr.Value)
+	})
+}
+
+func TestMustGet(t *testing.T) {
+	src := Map{"PORT": "8080"}
+	assert.Equal(t, uint16(8080), MustGet[uint16]("PORT", src))
+	assert.Panics(t, func() {
+		MustGet[uint16]("NOPE", src)
+	})
+}
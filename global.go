@@ -11,38 +11,55 @@ import (
 )
 
 var (
-	marshaler   rawconv.Marshaler
-	unmarshaler rawconv.Unmarshaler
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
 )
 
-func init() {
-	marshaler.Register(
-		reflect.TypeOf((*Marshaler)(nil)).Elem(),
-		func(v any) (string, error) {
-			b, err := v.(Marshaler).MarshalEnv()
-			if err != nil {
-				return "", err
-			}
-			return string(b), err
-		},
-	)
-
-	unmarshaler.Register(
-		reflect.TypeOf((*Unmarshaler)(nil)).Elem(),
-		func(val rawconv.Value, dest any) error {
-			return dest.(Unmarshaler).UnmarshalEnv(val.Bytes())
-		},
-	)
+func marshalEnv(v any) (string, error) {
+	b, err := v.(Marshaler).MarshalEnv()
+	if err != nil {
+		return "", err
+	}
+	return string(b), err
+}
+
+func unmarshalEnv(val rawconv.Value, dest any) error {
+	return dest.(Unmarshaler).UnmarshalEnv(val.Bytes())
+}
+
+// RegisterMarshalFunc registers the [rawconv.MarshalFunc] for typ with the
+// package-global [Registry].
+func RegisterMarshalFunc(typ reflect.Type, fn rawconv.MarshalFunc) {
+	global.RegisterMarshalFunc(typ, fn)
+}
+
+// RegisterUnmarshalFunc registers the [rawconv.UnmarshalFunc] for typ with the
+// package-global [Registry].
+func RegisterUnmarshalFunc(typ reflect.Type, fn rawconv.UnmarshalFunc) {
+	global.RegisterUnmarshalFunc(typ, fn)
+}
+
+// RegisterConverter registers the named [Converter] with the package-global
+// [Registry].
+func RegisterConverter(name string, conv Converter) {
+	global.RegisterConverter(name, conv)
+}
+
+// RegisterVariant registers the type of v as the variant with name of
+// interface type iface with the package-global [Registry]. See
+// [Registry.RegisterVariant] for details.
+func RegisterVariant(iface reflect.Type, name string, v any) {
+	global.RegisterVariant(iface, name, v)
 }
 
 // GetMarshalFunc returns the globally registered [rawconv.MarshalFunc] for
 // [reflect.Type] typ or nil if there is none registered.
 func GetMarshalFunc(typ reflect.Type) rawconv.MarshalFunc {
-	return marshaler.Func(typ)
+	return global.MarshalFunc(typ)
 }
 
 // GetUnmarshalFunc returns the globally registered [rawconv.UnmarshalFunc] for
 // [reflect.Type] typ or nil if there is none registered.
 func GetUnmarshalFunc(typ reflect.Type) rawconv.UnmarshalFunc {
-	return unmarshaler.Func(typ)
+	return global.UnmarshalFunc(typ)
 }
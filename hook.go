@@ -0,0 +1,73 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+
+	"github.com/go-pogo/env/envtag"
+	"github.com/go-pogo/errors"
+)
+
+// SkipField is used as a return value from a [FieldHook] to indicate the
+// remaining hooks of the chain, and the remaining handling of the field, should
+// be skipped. It is not returned as an error by any function.
+const SkipField errors.Msg = "skip field"
+
+// A FieldHook intercepts the decoding or encoding of a struct field. Hooks are
+// added to a [Decoder] or [Encoder] as before or after hooks and are called
+// in the order in which they are added. A hook may change the Value or Target
+// of the provided [HookField] to replace the value that is decoded or encoded.
+// Returning [SkipField] short-circuits the chain.
+type FieldHook func(f *HookField) error
+
+// HookField contains the struct field that is being decoded or encoded and is
+// passed to each [FieldHook].
+type HookField struct {
+	// Tag is the parsed tag of the struct field.
+	Tag envtag.Tag
+	// Field is the struct field.
+	Field reflect.StructField
+	// Path is the path to the struct field, e.g. Config.DB.Port.
+	Path string
+	// Value is the raw [Value] of the field's environment variable.
+	//   - When decoding, before hooks receive the looked up or default value
+	//     which is unmarshaled into Target afterward. After hooks receive the
+	//     value that was unmarshaled.
+	//   - When encoding, before hooks receive an empty value. After hooks
+	//     receive the marshaled value of Target, which is written afterward.
+	Value Value
+	// Target is the field's value.
+	//   - When decoding, after hooks receive Target with the unmarshaled
+	//     value. A before hook which sets Target should return [SkipField]
+	//     to prevent it from being overwritten.
+	//   - When encoding, before hooks receive the value which is marshaled
+	//     afterward. Target may be replaced with another value.
+	Target reflect.Value
+}
+
+func newHookField(rv reflect.Value, field fieldInfo, val Value) *HookField {
+	return &HookField{
+		Tag:    field.Tag,
+		Field:  field.StructField,
+		Path:   field.Path,
+		Value:  val,
+		Target: rv,
+	}
+}
+
+// runHooks calls each of hooks in order until one of them returns an error.
+// It reports true when the chain is short-circuited with [SkipField].
+func runHooks(hooks []FieldHook, f *HookField) (bool, error) {
+	for _, hook := range hooks {
+		if err := hook(f); err != nil {
+			if errors.Is(err, SkipField) {
+				return true, nil
+			}
+			return false, err
+		}
+	}
+	return false, nil
+}
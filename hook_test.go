@@ -0,0 +1,159 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder_WithBeforeHooks(t *testing.T) {
+	type Config struct {
+		Level string
+		Name  string `default:"  Foo  "`
+		Port  int
+		Token string
+	}
+
+	src := Map{"LEVEL": "DEBUG", "PORT": "8080", "TOKEN": "encrypted"}
+	trim := func(f *HookField) error {
+		f.Value = Value(strings.TrimSpace(f.Value.String()))
+		return nil
+	}
+	lower := func(f *HookField) error {
+		if f.Field.Type.Kind() == reflect.String {
+			f.Value = Value(strings.ToLower(f.Value.String()))
+		}
+		return nil
+	}
+	decrypt := func(f *HookField) error {
+		if f.Tag.Name != "TOKEN" {
+			return nil
+		}
+		f.Target.SetString("decrypted")
+		return SkipField
+	}
+
+	var calls []string
+	var have Config
+	assert.NoError(t, NewDecoder(src).
+		WithBeforeHooks(decrypt, trim).
+		WithBeforeHooks(lower, func(f *HookField) error {
+			calls = append(calls, f.Path)
+			return nil
+		}).
+		Decode(&have),
+	)
+	assert.Exactly(t, Config{
+		Level: "debug",
+		Name:  "foo",
+		Port:  8080,
+		Token: "decrypted",
+	}, have)
+	assert.Equal(t, []string{"Config.Level", "Config.Name", "Config.Port"}, calls)
+
+	t.Run("error", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(src).
+			WithBeforeHooks(func(f *HookField) error {
+				if f.Tag.Name == "PORT" {
+					return errors.New("hook error")
+				}
+				return nil
+			}).
+			Decode(&have)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.Equal(t, "PORT", decErr.Key)
+		assert.Equal(t, Value("8080"), decErr.Value)
+		assert.Equal(t, 0, have.Port)
+		assert.Equal(t, "DEBUG", have.Level)
+	})
+}
+
+func TestDecoder_WithAfterHooks(t *testing.T) {
+	type Config struct {
+		Port    int `validate:"min=1024"`
+		Workers int
+	}
+
+	src := Map{"PORT": "80", "WORKERS": "0"}
+
+	var have Config
+	var values []Value
+	assert.NoError(t, NewDecoder(src).
+		WithAfterHooks(func(f *HookField) error {
+			values = append(values, f.Value)
+			if f.Tag.Name == "PORT" && f.Target.Int() < 1024 {
+				f.Target.SetInt(f.Target.Int() + 8000)
+			}
+			return nil
+		}).
+		Decode(&have),
+	)
+	assert.Exactly(t, Config{Port: 8080}, have)
+	assert.Equal(t, []Value{"80", "0"}, values)
+
+	t.Run("skip", func(t *testing.T) {
+		var have Config
+		assert.NoError(t, NewDecoder(src).
+			WithAfterHooks(
+				func(*HookField) error { return SkipField },
+				func(*HookField) error { panic("should not be called") },
+			).
+			Decode(&have),
+		)
+		// validation is skipped as well
+		assert.Exactly(t, Config{Port: 80}, have)
+	})
+}
+
+func TestEncoder_WithHooks(t *testing.T) {
+	type Config struct {
+		Host     string
+		Port     int
+		Internal string
+		Password string
+	}
+
+	have := Config{Host: "localhost", Port: 8080, Internal: "foo", Password: "secret"}
+
+	var buf bytes.Buffer
+	assert.NoError(t, NewEncoder(&buf).
+		WithOptions(EncodeOptions{TakeValues: true}).
+		WithBeforeHooks(func(f *HookField) error {
+			switch f.Tag.Name {
+			case "INTERNAL":
+				return SkipField
+			case "PASSWORD":
+				f.Target = reflect.ValueOf(strings.Repeat("*", f.Target.Len()))
+			}
+			return nil
+		}).
+		WithAfterHooks(func(f *HookField) error {
+			if f.Tag.Name == "HOST" {
+				f.Value = Value(strings.ToUpper(f.Value.String()))
+			}
+			return nil
+		}).
+		Encode(have),
+	)
+	assert.Equal(t, "HOST=LOCALHOST\nPORT=8080\nPASSWORD=******\n", buf.String())
+
+	t.Run("error", func(t *testing.T) {
+		wantErr := errors.New("hook error")
+		assert.ErrorIs(t, NewEncoder(&buf).
+			WithAfterHooks(func(*HookField) error { return wantErr }).
+			Encode(have),
+			wantErr,
+		)
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyOrder orders the keys of a map before they are encoded by an [Encoder].
+// It returns the keys divided into one or more groups. An [Encoder] separates
+// each group with an empty line.
+type KeyOrder func(keys []string) [][]string
+
+// SortKeys is a [KeyOrder] which sorts keys in increasing order and returns
+// them as a single group.
+func SortKeys(keys []string) [][]string {
+	sort.Strings(keys)
+	return [][]string{keys}
+}
+
+// GroupKeys is a [KeyOrder] which sorts keys in increasing order and groups
+// them by their common prefix. The prefix of a key is the part before its first
+// underscore, e.g. DB_HOST and DB_PORT are grouped together.
+func GroupKeys(keys []string) [][]string {
+	sort.Strings(keys)
+
+	var res [][]string
+	var prev string
+	for i, key := range keys {
+		p := keyPrefix(key)
+		if i == 0 || p != prev {
+			res = append(res, []string{key})
+		} else {
+			res[len(res)-1] = append(res[len(res)-1], key)
+		}
+		prev = p
+	}
+	return res
+}
+
+func keyPrefix(key string) string {
+	if i := strings.IndexRune(key, '_'); i > 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// SortKeysFunc returns a [KeyOrder] which sorts keys using cmp and returns them
+// as a single group. The cmp function should return a negative number when a
+// should be placed before b, a positive number when a should be placed after b
+// and zero when their order does not matter.
+func SortKeysFunc(cmp func(a, b string) int) KeyOrder {
+	return func(keys []string) [][]string {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return cmp(keys[i], keys[j]) < 0
+		})
+		return [][]string{keys}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortKeys(t *testing.T) {
+	assert.Exactly(t,
+		[][]string{{"BAR", "FOO", "QUX"}},
+		SortKeys([]string{"QUX", "FOO", "BAR"}),
+	)
+}
+
+func TestGroupKeys(t *testing.T) {
+	tests := map[string]struct {
+		keys []string
+		want [][]string
+	}{
+		"empty": {},
+		"single": {
+			keys: []string{"FOO"},
+			want: [][]string{{"FOO"}},
+		},
+		"groups": {
+			keys: []string{"HTTP_PORT", "DB_PORT", "FOO", "DB_HOST", "HTTP_ADDR", "_X"},
+			want: [][]string{
+				{"DB_HOST", "DB_PORT"},
+				{"FOO"},
+				{"HTTP_ADDR", "HTTP_PORT"},
+				{"_X"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Exactly(t, tc.want, GroupKeys(tc.keys))
+		})
+	}
+}
+
+func TestSortKeysFunc(t *testing.T) {
+	byLen := SortKeysFunc(func(a, b string) int { return len(a) - len(b) })
+	assert.Exactly(t,
+		[][]string{{"A", "CC", "BB", "DDD"}},
+		byLen([]string{"DDD", "A", "CC", "BB"}),
+	)
+}
+
+func TestEncoder_KeyOrder(t *testing.T) {
+	input := Map{
+		"HTTP_PORT": "80",
+		"DB_PORT":   "5432",
+		"FOO":       "bar",
+		"DB_HOST":   "db",
+	}
+
+	tests := map[string]struct {
+		order KeyOrder
+		want  string
+	}{
+		"default": {
+			want: "DB_HOST=db\nDB_PORT=5432\nFOO=bar\nHTTP_PORT=80\n",
+		},
+		"grouped": {
+			order: GroupKeys,
+			want:  "DB_HOST=db\nDB_PORT=5432\n\nFOO=bar\n\nHTTP_PORT=80\n",
+		},
+		"custom": {
+			order: SortKeysFunc(func(a, b string) int { return strings.Compare(b, a) }),
+			want:  "HTTP_PORT=80\nFOO=bar\nDB_PORT=5432\nDB_HOST=db\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf strings.Builder
+			enc := NewEncoder(&buf)
+			if tc.order != nil {
+				enc.WithKeyOrder(tc.order)
+			}
+
+			assert.NoError(t, enc.Encode(input))
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("zero options", func(t *testing.T) {
+		var buf strings.Builder
+		enc := NewEncoder(&buf).WithOptions(EncodeOptions{})
+
+		assert.NoError(t, enc.Encode(map[string]Value{"b": "2", "a": "1"}))
+		assert.Equal(t, "a=1\nb=2\n", buf.String())
+	})
+}
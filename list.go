@@ -0,0 +1,315 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-pogo/env/envtag"
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+const (
+	DefaultItemsSeparator    = rawconv.DefaultItemsSeparator
+	DefaultKeyValueSeparator = rawconv.DefaultKeyValueSeparator
+)
+
+// separators contains the separators used to split and join the items of
+// slices, arrays and maps.
+type separators struct {
+	items    string
+	keyValue string
+}
+
+func newSeparators(items, keyValue string) separators {
+	if items == "" {
+		items = DefaultItemsSeparator
+	}
+	if keyValue == "" {
+		keyValue = DefaultKeyValueSeparator
+	}
+	return separators{items: items, keyValue: keyValue}
+}
+
+func tagSeparators(tag envtag.Tag) separators {
+	return newSeparators(tag.Separator, tag.KeyValueSeparator)
+}
+
+// isListType indicates if typ is a slice, array or map type which is not
+// handled by a registered (un)marshal func of its own.
+func isListType(typ reflect.Type, isKnownType func(reflect.Type) bool) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return !isKnownType(typ)
+	default:
+		return false
+	}
+}
+
+// unmarshalField unmarshals val into rv using the field's named converter, or
+// decodes it using the field's binary encoding. Otherwise it uses
+// unmarshalValue with the field's separators.
+func (r *Registry) unmarshalField(val Value, rv reflect.Value, tag envtag.Tag) error {
+	if tag.Converter == "" {
+		if tag.Encoding != "" {
+			return unmarshalBytes(val, rv, tag.Encoding)
+		}
+		return r.unmarshalValue(val, rv, tagSeparators(tag))
+	}
+
+	conv, ok := r.Converter(tag.Converter)
+	if !ok || conv.Unmarshal == nil {
+		return errors.Wrap(ErrUnknownConverter, tag.Converter)
+	}
+	return conv.Unmarshal.Exec(val, rv)
+}
+
+// marshalField marshals rv using the field's named converter, or encodes it
+// using the field's binary encoding. Otherwise it uses marshalValue with the
+// field's separators.
+func (r *Registry) marshalField(rv reflect.Value, tag envtag.Tag) (Value, error) {
+	if tag.Converter == "" {
+		if tag.Encoding != "" {
+			return marshalBytes(rv, tag.Encoding)
+		}
+		return r.marshalValue(rv, tagSeparators(tag))
+	}
+
+	conv, ok := r.Converter(tag.Converter)
+	if !ok || conv.Marshal == nil {
+		return "", errors.Wrap(ErrUnknownConverter, tag.Converter)
+	}
+	return conv.Marshal.Exec(rv)
+}
+
+// unmarshalValue unmarshals val into rv. Slices, arrays and maps are split into
+// their items using the provided separators, each item, or key and value of a
+// map item, may be wrapped in double quotes when it contains a separator.
+func (r *Registry) unmarshalValue(val Value, rv reflect.Value, seps separators) error {
+	if !isListType(rv.Type(), r.knownByUnmarshaler) {
+		return r.unmarshal(val, rv)
+	}
+	if val.IsEmpty() {
+		return nil
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			if !rv.CanSet() {
+				return errors.New(rawconv.ErrUnableToSet)
+			}
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	var kvsep string
+	if rv.Kind() == reflect.Map {
+		kvsep = seps.keyValue
+	}
+
+	items := splitItems(val.String(), seps.items, kvsep)
+	typ := rv.Type()
+
+	switch rv.Kind() {
+	case reflect.Array:
+		if len(items) > rv.Len() {
+			return errors.New(rawconv.ErrArrayTooManyValues)
+		}
+		for i, item := range items {
+			if err := r.unmarshal(Value(unquoteItem(item)), rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		slice := reflect.MakeSlice(typ, len(items), len(items))
+		for i, item := range items {
+			if err := r.unmarshal(Value(unquoteItem(item)), slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+
+	default: // reflect.Map
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(typ, len(items)))
+		}
+		for _, item := range items {
+			k, v, found := cutKeyValue(item, seps.keyValue)
+			if !found {
+				return errors.New(rawconv.ErrMapInvalidFormat)
+			}
+
+			key := reflect.New(typ.Key()).Elem()
+			if err := r.unmarshal(Value(k), key); err != nil {
+				return err
+			}
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := r.unmarshal(Value(v), elem); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, elem)
+		}
+		return nil
+	}
+}
+
+// marshalValue marshals rv into a [Value]. The items of slices, arrays and
+// maps are joined using the provided separators, each item which contains a
+// separator is wrapped in double quotes.
+func (r *Registry) marshalValue(rv reflect.Value, seps separators) (Value, error) {
+	if !isListType(rv.Type(), r.knownByMarshaler) {
+		return r.marshal(rv)
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+
+	var items []string
+	if rv.Kind() == reflect.Map {
+		keys := rv.MapKeys()
+		items = make([]string, 0, len(keys))
+		for _, key := range keys {
+			k, err := r.marshal(key)
+			if err != nil {
+				return "", err
+			}
+			v, err := r.marshal(rv.MapIndex(key))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, quoteItem(k.String(), seps.items, seps.keyValue)+
+				seps.keyValue+quoteItem(v.String(), seps.items))
+		}
+		// map iteration order is random, sort items for a stable result
+		SortKeys(items)
+	} else {
+		items = make([]string, rv.Len())
+		for i := range items {
+			v, err := r.marshal(rv.Index(i))
+			if err != nil {
+				return "", err
+			}
+			items[i] = quoteItem(v.String(), seps.items)
+		}
+	}
+
+	return Value(strings.Join(items, seps.items)), nil
+}
+
+// splitItems splits str into items separated by sep. Items that start with a
+// double quote may contain sep until the closing double quote, within those a
+// double quote or backslash can be escaped with a backslash. When kvsep is not
+// empty, the value after the first kvsep of an item may be quoted as well.
+// Quotes are kept, use unquoteItem or cutKeyValue to remove them. Any
+// whitespace around an item is trimmed.
+func splitItems(str, sep, kvsep string) []string {
+	var items []string
+	for {
+		end := scanItemPart(str, 0, sep, kvsep)
+		if kvsep != "" && strings.HasPrefix(str[end:], kvsep) {
+			end = scanItemPart(str, end+len(kvsep), sep)
+		}
+		items = append(items, strings.TrimSpace(str[:end]))
+		if end == len(str) {
+			return items
+		}
+		str = str[end+len(sep):]
+	}
+}
+
+// cutKeyValue cuts map item around the first kvsep which is not within a
+// quoted key, and returns the unquoted key and value.
+func cutKeyValue(item, kvsep string) (key, val string, found bool) {
+	end := scanItemPart(item, 0, kvsep)
+	if end == len(item) {
+		return "", "", false
+	}
+	return unquoteItem(item[:end]), unquoteItem(item[end+len(kvsep):]), true
+}
+
+// scanItemPart returns the index of the first of stops in str, starting at
+// index i, or the length of str when none are found. When the part starting
+// at i begins with a double quote, stops within the quoted text are skipped.
+func scanItemPart(str string, i int, stops ...string) int {
+	j := i
+	for j < len(str) && (str[j] == ' ' || str[j] == '\t') {
+		j++
+	}
+	if j < len(str) && str[j] == '"' {
+		if k := closingQuote(str, j); k >= 0 {
+			i = k + 1
+		}
+	}
+
+	for ; i < len(str); i++ {
+		for _, stop := range stops {
+			if stop != "" && strings.HasPrefix(str[i:], stop) {
+				return i
+			}
+		}
+	}
+	return len(str)
+}
+
+// closingQuote returns the index of the unescaped double quote which closes
+// the quote at index i, or -1 when there is none.
+func closingQuote(str string, i int) int {
+	for i++; i < len(str); i++ {
+		switch str[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteItem trims item and removes its surrounding double quotes, when
+// present, together with the escaping of the quoted text. Any other double
+// quotes are kept as is.
+func unquoteItem(item string) string {
+	item = strings.TrimSpace(item)
+	if len(item) < 2 || item[0] != '"' || closingQuote(item, 0) != len(item)-1 {
+		return item
+	}
+
+	var buf strings.Builder
+	for i := 1; i < len(item)-1; i++ {
+		if item[i] == '\\' {
+			i++
+		}
+		buf.WriteByte(item[i])
+	}
+	return buf.String()
+}
+
+// quoteItem wraps item in double quotes when it contains any of seps, starts
+// with a double quote or has surrounding whitespace, so it is not altered by
+// splitItems. Double quotes and backslashes within the quoted text are
+// escaped.
+func quoteItem(item string, seps ...string) string {
+	quote := item != strings.TrimSpace(item) || strings.HasPrefix(item, `"`)
+	for i := 0; !quote && i < len(seps); i++ {
+		quote = strings.Contains(item, seps[i])
+	}
+	if !quote {
+		return item
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item) + `"`
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitItems(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		sep   string
+		want  []string
+	}{
+		"single":        {input: "a", sep: ",", want: []string{"a"}},
+		"default":       {input: "a, b ,c", sep: ",", want: []string{"a", "b", "c"}},
+		"custom":        {input: "a.com;b.com", sep: ";", want: []string{"a.com", "b.com"}},
+		"multi char":    {input: "a||b", sep: "||", want: []string{"a", "b"}},
+		"quoted":        {input: `a;"b;c";d`, sep: ";", want: []string{"a", "b;c", "d"}},
+		"escaped":       {input: `"say \"hi\"",x`, sep: ",", want: []string{`say "hi"`, "x"}},
+		"empty items":   {input: "a,,b", sep: ",", want: []string{"a", "", "b"}},
+		"quoted empty":  {input: `""`, sep: ",", want: []string{""}},
+		"inner quotes":  {input: `say "hi",x`, sep: ",", want: []string{`say "hi"`, "x"}},
+		"odd quote":     {input: `a"b,c`, sep: ",", want: []string{`a"b`, "c"}},
+		"unclosed":      {input: `"a,b`, sep: ",", want: []string{`"a`, "b"}},
+		"trailing text": {input: `"a,b" c,d`, sep: ",", want: []string{`"a,b" c`, "d"}},
+		"backslash":     {input: `a\b,c`, sep: ",", want: []string{`a\b`, "c"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			items := splitItems(tc.input, tc.sep, "")
+			for i, item := range items {
+				items[i] = unquoteItem(item)
+			}
+			assert.Exactly(t, tc.want, items)
+		})
+	}
+}
+
+func TestCutKeyValue(t *testing.T) {
+	tests := map[string][2]string{
+		"a=b":              {"a", "b"},
+		" a = b ":          {"a", "b"},
+		"url=http://x?a=b": {"url", "http://x?a=b"},
+		`"a=b"=c`:          {"a=b", "c"},
+		`"a=b"="c,d"`:      {"a=b", "c,d"},
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			k, v, found := cutKeyValue(input, "=")
+			assert.True(t, found)
+			assert.Equal(t, want, [2]string{k, v})
+		})
+	}
+
+	_, _, found := cutKeyValue(`"a=b"`, "=")
+	assert.False(t, found)
+}
+
+func TestQuoteItem(t *testing.T) {
+	items := []string{"a", "b;c", `say "hi"`, `"quoted"`, " space ", `back\slash`, "k:v"}
+
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = quoteItem(item, ";", ":")
+	}
+
+	have := strings.Join(quoted, ";")
+	assert.Equal(t, `a;"b;c";say "hi";"\"quoted\"";" space ";back\slash;"k:v"`, have)
+
+	split := splitItems(have, ";", "")
+	for i, item := range split {
+		split[i] = unquoteItem(item)
+	}
+	assert.Exactly(t, items, split)
+}
+
+func TestSeparators(t *testing.T) {
+	type subj struct {
+		Hosts  []string          `env:"ALLOWED_HOSTS,sep=;"`
+		Labels map[string]string `env:",kvsep=:"`
+		Ports  [2]int            `env:",sep=|" default:"80|443"`
+	}
+
+	const input = `ALLOWED_HOSTS='a.com;"b;c.com"'
+LABELS=team:core,tier:1`
+
+	want := subj{
+		Hosts:  []string{"a.com", "b;c.com"},
+		Labels: map[string]string{"team": "core", "tier": "1"},
+		Ports:  [2]int{80, 443},
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		var have subj
+		assert.NoError(t, Unmarshal([]byte(input), &have))
+		assert.Exactly(t, want, have)
+	})
+	t.Run("encode", func(t *testing.T) {
+		var buf strings.Builder
+		enc := NewEncoder(&buf)
+		enc.TakeValues = true
+
+		assert.NoError(t, enc.Encode(want))
+		assert.Equal(t, input+"\nPORTS=80|443\n", buf.String())
+
+		var have subj
+		assert.NoError(t, Unmarshal([]byte(buf.String()), &have))
+		assert.Exactly(t, want, have)
+	})
+	t.Run("extract", func(t *testing.T) {
+		have, err := Extract(subj{})
+		assert.NoError(t, err)
+		assert.Equal(t, [2]int{80, 443}, have["PORTS"])
+	})
+	t.Run("format", func(t *testing.T) {
+		have, err := Format("HOSTS", []string{"a", "b,c"})
+		assert.NoError(t, err)
+		assert.Equal(t, `HOSTS='a,"b,c"'`, have)
+
+		have, err = FormatSeparators(";", ":")("LABELS", map[string]string{"team": "core", "tier": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "LABELS=team:core;tier:1", have)
+	})
+	t.Run("map round trip", func(t *testing.T) {
+		type subj struct {
+			Labels map[string]string `env:",sep=;,kvsep=:"`
+		}
+
+		want := subj{Labels: map[string]string{
+			"a:b":   "c;d",
+			"url":   "http://x:80",
+			`"q"`:   `say "hi"`,
+			"plain": "value",
+		}}
+
+		var buf strings.Builder
+		enc := NewEncoder(&buf)
+		enc.TakeValues = true
+		assert.NoError(t, enc.Encode(want))
+
+		var have subj
+		assert.NoError(t, Unmarshal([]byte(buf.String()), &have))
+		assert.Exactly(t, want, have)
+	})
+}
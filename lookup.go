@@ -8,8 +8,13 @@ import (
 	"github.com/go-pogo/errors"
 )
 
-// ErrNotFound is returned when a [Lookup] call cannot find a matching key.
-const ErrNotFound errors.Msg = "not found"
+const (
+	// ErrNotFound is returned when a [Lookup] call cannot find a matching key.
+	ErrNotFound errors.Msg = "not found"
+	// ErrNotMapper is returned when all keys of a [Lookupper] are required,
+	// but it does not implement [Mapper].
+	ErrNotMapper errors.Msg = "Lookupper does not implement Mapper"
+)
 
 // IsNotFound tests whether the provided error is [ErrNotFound].
 func IsNotFound(err error) bool {
@@ -78,3 +83,31 @@ func chain(lookuppers ...Lookupper) (Lookupper, bool) {
 func (c chainLookupper) Lookup(key string) (Value, error) {
 	return Lookup(key, c...)
 }
+
+// environ returns a [Map] of all environment variables that can be looked up
+// from l. It supports any [Mapper], (chains of) [Lookupper](s) which implement
+// [Mapper] and [Replacer]s that wrap a [Mapper]. Values are not replaced.
+func environ(l Lookupper) (Map, error) {
+	switch x := l.(type) {
+	case Mapper:
+		return x.Environ()
+
+	case *Replacer:
+		return environ(x.lookupper)
+
+	case chainLookupper:
+		res := make(Map, 8)
+		// the first Lookupper in the chain has precedence
+		for i := len(x) - 1; i >= 0; i-- {
+			m, err := environ(x[i])
+			if err != nil {
+				return nil, err
+			}
+			res.MergeValues(m)
+		}
+		return res, nil
+
+	default:
+		return nil, errors.New(ErrNotMapper)
+	}
+}
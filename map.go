@@ -5,6 +5,9 @@
 package env
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/go-pogo/errors"
 )
 
@@ -51,3 +54,177 @@ func (m Map) Environ() (Map, error) {
 	clone.MergeValues(m)
 	return clone, nil
 }
+
+// FromEnviron returns a [Map] with the key value pairs from environ. Each
+// entry is expected to be in the form "key=value", as returned by
+// [os.Environ] or used by the Env field of [os/exec.Cmd]. Entries without a
+// key are skipped. When a key occurs more than once, the last value wins.
+func FromEnviron(environ []string) Map {
+	res := make(Map, len(environ))
+	for _, e := range environ {
+		i := strings.IndexRune(e, '=')
+		if i <= 0 {
+			// exclude empty keys and env variables like =::=:: or =R:=R:\\
+			continue
+		}
+
+		res[e[:i]] = Value(e[i+1:])
+	}
+	return res
+}
+
+// Strings returns the key value pairs of the [Map] as a slice of "key=value"
+// strings, sorted by key. The result is suitable for use as the Env field of
+// [os/exec.Cmd].
+func (m Map) Strings() []string {
+	keys := m.Keys()
+	res := make([]string, len(keys))
+	for i, k := range keys {
+		res[i] = k + "=" + m[k].String()
+	}
+	return res
+}
+
+// CmdEnv returns a sorted slice of "key=value" strings, suitable for use as
+// the Env field of [os/exec.Cmd]. It starts with the values from base, merges
+// the values from overlay on top of them and finally removes the keys in unset.
+// The base and overlay [Map] are not modified.
+//
+//	cmd := exec.Command("app")
+//	cmd.Env = env.CmdEnv(env.Environ(), env.Map{"DEBUG": "1"}, "HOME")
+func CmdEnv(base, overlay Map, unset ...string) []string {
+	return base.Overlay(overlay).Without(unset...).Strings()
+}
+
+// Keys returns the keys of the [Map], sorted in increasing order.
+func (m Map) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Overlay returns a new [Map] with the values of this [Map], overwritten by
+// the values of each of the overlays, in order.
+func (m Map) Overlay(overlays ...Map) Map {
+	n := len(m)
+	for _, o := range overlays {
+		n += len(o)
+	}
+
+	res := make(Map, n)
+	res.MergeValues(m)
+	for _, o := range overlays {
+		res.MergeValues(o)
+	}
+	return res
+}
+
+// Without returns a new [Map] with the values of this [Map], except for the
+// provided keys.
+func (m Map) Without(keys ...string) Map {
+	res := make(Map, len(m))
+	res.MergeValues(m)
+	for _, k := range keys {
+		delete(res, k)
+	}
+	return res
+}
+
+// Filter returns a new [Map] with only the key value pairs for which fn
+// returns true.
+func (m Map) Filter(fn func(key string, val Value) bool) Map {
+	res := make(Map, len(m))
+	for k, v := range m {
+		if fn(k, v) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// FilterPrefix returns a new [Map] with only the key value pairs of which the
+// key starts with prefix.
+func (m Map) FilterPrefix(prefix string) Map {
+	return m.Filter(func(key string, _ Value) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// StripPrefix returns a new [Map] with the key value pairs of which the key
+// starts with prefix, with prefix removed from those keys. Keys that do not
+// start with prefix, or are equal to it, are omitted.
+func (m Map) StripPrefix(prefix string) Map {
+	res := make(Map, len(m))
+	for k, v := range m {
+		if len(k) > len(prefix) && strings.HasPrefix(k, prefix) {
+			res[k[len(prefix):]] = v
+		}
+	}
+	return res
+}
+
+// AddPrefix returns a new [Map] with prefix added to all keys.
+func (m Map) AddPrefix(prefix string) Map {
+	res := make(Map, len(m))
+	for k, v := range m {
+		res[prefix+k] = v
+	}
+	return res
+}
+
+// Diff returns a [MapDiff] which describes the changes required to go from
+// this [Map] to the other [Map].
+//
+//	diff := env.Environ().Diff(env.Environ().Overlay(m))
+func (m Map) Diff(other Map) MapDiff {
+	var diff MapDiff
+	for k, v := range m {
+		ov, ok := other[k]
+		if !ok {
+			if diff.Removed == nil {
+				diff.Removed = make(Map)
+			}
+			diff.Removed[k] = v
+		} else if ov != v {
+			if diff.Changed == nil {
+				diff.Changed = make(map[string]ValueChange)
+			}
+			diff.Changed[k] = ValueChange{Old: v, New: ov}
+		}
+	}
+	for k, v := range other {
+		if _, ok := m[k]; !ok {
+			if diff.Added == nil {
+				diff.Added = make(Map)
+			}
+			diff.Added[k] = v
+		}
+	}
+	return diff
+}
+
+// MapDiff contains the differences between two [Map]s, see [Map.Diff].
+type MapDiff struct {
+	// Added contains the key value pairs that only exist in the other [Map].
+	Added Map
+	// Removed contains the key value pairs that only exist in the original
+	// [Map].
+	Removed Map
+	// Changed contains the keys of which the value differs between both
+	// [Map]s.
+	Changed map[string]ValueChange
+}
+
+// ValueChange describes a changed [Value].
+type ValueChange struct {
+	Old Value
+	New Value
+}
+
+// IsEmpty indicates if there are no differences.
+func (d MapDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
@@ -69,3 +69,91 @@ func TestMap_Environ(t *testing.T) {
 	src["foo"] = "qux"
 	assert.Equal(t, Value("bar"), clone["foo"])
 }
+
+func TestFromEnviron(t *testing.T) {
+	have := FromEnviron([]string{
+		"FOO=bar",
+		"QUX=",
+		"EQ=a=b",
+		"=::=::",
+		"NOPE",
+		"FOO=baz",
+	})
+	assert.Exactly(t, Map{
+		"FOO": "baz",
+		"QUX": "",
+		"EQ":  "a=b",
+	}, have)
+}
+
+func TestMap_Strings(t *testing.T) {
+	m := Map{"qux": "xoo", "foo": "bar", "bar": ""}
+	assert.Exactly(t, []string{"bar=", "foo=bar", "qux=xoo"}, m.Strings())
+	assert.Exactly(t, m, FromEnviron(m.Strings()))
+}
+
+func TestCmdEnv(t *testing.T) {
+	base := Map{"FOO": "bar", "HOME": "/root", "QUX": "xoo"}
+	overlay := Map{"FOO": "baz", "DEBUG": "1"}
+
+	assert.Exactly(t,
+		[]string{"DEBUG=1", "FOO=baz", "QUX=xoo"},
+		CmdEnv(base, overlay, "HOME"),
+	)
+	assert.Exactly(t, Map{"FOO": "bar", "HOME": "/root", "QUX": "xoo"}, base)
+	assert.Exactly(t, []string{}, CmdEnv(nil, nil))
+}
+
+func TestMap_Keys(t *testing.T) {
+	assert.Exactly(t, []string{"bar", "foo", "qux"}, Map{"qux": "", "foo": "", "bar": ""}.Keys())
+	assert.Exactly(t, []string{}, Map{}.Keys())
+}
+
+func TestMap_Overlay(t *testing.T) {
+	m := Map{"foo": "bar", "qux": "xoo"}
+	have := m.Overlay(Map{"foo": "baz"}, Map{"bar": "baz", "foo": "qux"})
+	assert.Exactly(t, Map{"foo": "qux", "qux": "xoo", "bar": "baz"}, have)
+	assert.Exactly(t, Map{"foo": "bar", "qux": "xoo"}, m)
+}
+
+func TestMap_Without(t *testing.T) {
+	m := Map{"foo": "bar", "qux": "xoo"}
+	assert.Exactly(t, Map{"qux": "xoo"}, m.Without("foo", "nop"))
+	assert.Len(t, m, 2)
+}
+
+func TestMap_Filter(t *testing.T) {
+	m := Map{"APP_FOO": "bar", "APP_QUX": "", "OTHER": "x"}
+	t.Run("func", func(t *testing.T) {
+		assert.Exactly(t, Map{"APP_FOO": "bar", "OTHER": "x"}, m.Filter(func(_ string, v Value) bool {
+			return !v.IsEmpty()
+		}))
+	})
+	t.Run("prefix", func(t *testing.T) {
+		assert.Exactly(t, Map{"APP_FOO": "bar", "APP_QUX": ""}, m.FilterPrefix("APP_"))
+	})
+}
+
+func TestMap_StripPrefix(t *testing.T) {
+	m := Map{"APP_FOO": "bar", "APP_": "nop", "OTHER": "x"}
+	have := m.StripPrefix("APP_")
+	assert.Exactly(t, Map{"FOO": "bar"}, have)
+	assert.Exactly(t, Map{"APP_FOO": "bar"}, have.AddPrefix("APP_"))
+}
+
+func TestMap_Diff(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		diff := Map{"foo": "bar"}.Diff(Map{"foo": "bar"})
+		assert.True(t, diff.IsEmpty())
+		assert.Exactly(t, MapDiff{}, diff)
+	})
+	t.Run("changes", func(t *testing.T) {
+		diff := Map{"foo": "bar", "qux": "xoo"}.Diff(Map{"foo": "baz", "bar": ""})
+		assert.False(t, diff.IsEmpty())
+		assert.Exactly(t, MapDiff{
+			Added:   Map{"bar": ""},
+			Removed: Map{"qux": "xoo"},
+			Changed: map[string]ValueChange{"foo": {Old: "bar", New: "baz"}},
+		}, diff)
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"github.com/go-pogo/errors"
+)
+
+// OrderedMapper provides an [OrderedMap] of keys and values representing the
+// environment, in the order in which they are defined.
+type OrderedMapper interface {
+	OrderedEnviron() (*OrderedMap, error)
+}
+
+var (
+	_ LookupMapper  = (*OrderedMap)(nil)
+	_ OrderedMapper = (*OrderedMap)(nil)
+)
+
+// OrderedMap is a collection of key value pairs which, unlike [Map], remembers
+// the order in which its keys were first added. Its zero value is ready to use.
+type OrderedMap struct {
+	keys []string
+	vals Map
+}
+
+// NewOrderedMap returns a new [OrderedMap] containing the provided
+// [NamedValue](s), in order.
+func NewOrderedMap(nvs ...NamedValue) *OrderedMap {
+	om := &OrderedMap{
+		keys: make([]string, 0, len(nvs)),
+		vals: make(Map, len(nvs)),
+	}
+	for _, nv := range nvs {
+		om.Set(nv.Name, nv.Value)
+	}
+	return om
+}
+
+// Len returns the amount of key value pairs in the [OrderedMap].
+func (om *OrderedMap) Len() int { return len(om.keys) }
+
+// Set the [Value] of key. A key which is new to the [OrderedMap] is added to
+// the end, an existing key keeps its position.
+func (om *OrderedMap) Set(key string, val Value) {
+	if om.vals == nil {
+		om.vals = make(Map, 4)
+	}
+	if _, ok := om.vals[key]; !ok {
+		om.keys = append(om.keys, key)
+	}
+	om.vals[key] = val
+}
+
+// Delete removes key and its [Value] from the [OrderedMap].
+func (om *OrderedMap) Delete(key string) {
+	if _, ok := om.vals[key]; !ok {
+		return
+	}
+
+	delete(om.vals, key)
+	for i, k := range om.keys {
+		if k == key {
+			om.keys = append(om.keys[:i], om.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Lookup retrieves the [Value] of the environment variable named by the key.
+// It returns an [ErrNotFound] error if the key is not present.
+func (om *OrderedMap) Lookup(key string) (Value, error) {
+	if v, ok := om.vals[key]; ok {
+		return v, nil
+	}
+	return "", errors.New(ErrNotFound)
+}
+
+// Merge the key value pairs of src into this [OrderedMap], in order. Existing
+// keys are overwritten with the value of the key in src, but keep their
+// position. A nil src is ignored.
+func (om *OrderedMap) Merge(src *OrderedMap) {
+	if src == nil {
+		return
+	}
+	for _, k := range src.keys {
+		om.Set(k, src.vals[k])
+	}
+}
+
+// Keys returns the keys of the [OrderedMap] in order.
+func (om *OrderedMap) Keys() []string {
+	keys := make([]string, len(om.keys))
+	copy(keys, om.keys)
+	return keys
+}
+
+// NamedValues returns the key value pairs of the [OrderedMap] as a slice of
+// [NamedValue], in order.
+func (om *OrderedMap) NamedValues() []NamedValue {
+	res := make([]NamedValue, len(om.keys))
+	for i, k := range om.keys {
+		res[i] = NamedValue{Name: k, Value: om.vals[k]}
+	}
+	return res
+}
+
+// Map returns the key value pairs of the [OrderedMap] as a new, unordered
+// [Map].
+func (om *OrderedMap) Map() Map {
+	res := make(Map, len(om.vals))
+	res.MergeValues(om.vals)
+	return res
+}
+
+// Environ returns the key value pairs of the [OrderedMap] as a new [Map].
+func (om *OrderedMap) Environ() (Map, error) { return om.Map(), nil }
+
+// OrderedEnviron returns a copy of the [OrderedMap].
+func (om *OrderedMap) OrderedEnviron() (*OrderedMap, error) {
+	return NewOrderedMap(om.NamedValues()...), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap(t *testing.T) {
+	t.Run("zero value", func(t *testing.T) {
+		var om OrderedMap
+		om.Set("foo", "bar")
+		assert.Equal(t, 1, om.Len())
+		assert.Exactly(t, []string{"foo"}, om.Keys())
+	})
+	t.Run("set", func(t *testing.T) {
+		om := NewOrderedMap(
+			NamedValue{Name: "qux", Value: "xoo"},
+			NamedValue{Name: "foo", Value: "bar"},
+		)
+		om.Set("bar", "baz")
+		om.Set("qux", "x00")
+
+		assert.Exactly(t, []NamedValue{
+			{Name: "qux", Value: "x00"},
+			{Name: "foo", Value: "bar"},
+			{Name: "bar", Value: "baz"},
+		}, om.NamedValues())
+	})
+	t.Run("delete", func(t *testing.T) {
+		om := NewOrderedMap(
+			NamedValue{Name: "qux", Value: "xoo"},
+			NamedValue{Name: "foo", Value: "bar"},
+		)
+		om.Delete("qux")
+		om.Delete("nop")
+
+		assert.Exactly(t, []string{"foo"}, om.Keys())
+		_, err := om.Lookup("qux")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("merge", func(t *testing.T) {
+		om := NewOrderedMap(NamedValue{Name: "foo", Value: "bar"})
+		om.Merge(NewOrderedMap(
+			NamedValue{Name: "qux", Value: "xoo"},
+			NamedValue{Name: "foo", Value: "baz"},
+		))
+
+		assert.Exactly(t, []NamedValue{
+			{Name: "foo", Value: "baz"},
+			{Name: "qux", Value: "xoo"},
+		}, om.NamedValues())
+
+		m, err := om.Environ()
+		assert.NoError(t, err)
+		assert.Exactly(t, Map{"foo": "baz", "qux": "xoo"}, m)
+	})
+	t.Run("merge into zero value", func(t *testing.T) {
+		var om OrderedMap
+		om.Merge(nil)
+		assert.Equal(t, 0, om.Len())
+
+		om.Merge(NewOrderedMap(NamedValue{Name: "foo", Value: "bar"}))
+		assert.Exactly(t, []string{"foo"}, om.Keys())
+
+		val, err := om.Lookup("foo")
+		assert.NoError(t, err)
+		assert.Exactly(t, Value("bar"), val)
+	})
+}
+
+func TestReader_OrderedEnviron(t *testing.T) {
+	const input = "ZOO=1\nFOO=bar\nAPP=x\nZOO=2"
+
+	om, err := NewReader(strings.NewReader(input)).OrderedEnviron()
+	assert.NoError(t, err)
+	assert.Exactly(t, []NamedValue{
+		{Name: "ZOO", Value: "2"},
+		{Name: "FOO", Value: "bar"},
+		{Name: "APP", Value: "x"},
+	}, om.NamedValues())
+}
@@ -25,10 +25,17 @@ type Value = rawconv.Value
 type NamedValue struct {
 	Name  string
 	Value Value
+	// Secret indicates Value is sensitive and is redacted by GoString and
+	// when encoded by an [Encoder].
+	Secret bool
 }
 
 func (nv NamedValue) GoString() string {
-	return `env.NamedValue(` + nv.Name + `="` + nv.Value.String() + `")`
+	val := nv.Value.String()
+	if nv.Secret {
+		val = redact(val)
+	}
+	return `env.NamedValue(` + nv.Name + `="` + val + `")`
 }
 
 // Parse parses a string containing a possible name/value pair. Any whitespace
@@ -44,13 +51,19 @@ func Parse(str string) (NamedValue, error) {
 
 type ParseError struct {
 	Err error
+	// Str is the string that failed to parse. It may contain a sensitive
+	// value, which is why it is redacted in the error message.
 	Str string
 }
 
 func (e *ParseError) Unwrap() error { return e.Err }
 
 func (e *ParseError) Error() string {
-	return fmt.Sprintf("error while parsing `%s`", e.Str)
+	str := e.Str
+	if i := strings.IndexRune(str, '='); i >= 0 {
+		str = str[:i+1] + redact(strings.TrimSpace(str[i+1:]))
+	}
+	return fmt.Sprintf("error while parsing `%s`", str)
 }
 
 func parse(str string) (NamedValue, error) {
@@ -90,7 +103,7 @@ func parse(str string) (NamedValue, error) {
 
 	val := parts[1]
 	if val == "" {
-		return NamedValue{key, Value(val)}, nil
+		return NamedValue{Name: key, Value: Value(val)}, nil
 	}
 
 	if unicode.IsSpace(rune(val[0])) {
@@ -126,7 +139,7 @@ func parse(str string) (NamedValue, error) {
 		}
 	}
 
-	return NamedValue{key, Value(val)}, nil
+	return NamedValue{Name: key, Value: Value(val)}, nil
 }
 
 func parseQuotedValue(val string, q rune) (string, error) {
@@ -15,11 +15,16 @@ type LookupMapper interface {
 	Mapper
 }
 
-var _ LookupMapper = (*Reader)(nil)
+var (
+	_ LookupMapper  = (*Reader)(nil)
+	_ OrderedMapper = (*Reader)(nil)
+)
 
 type Reader struct {
 	scanner *Scanner
 	found   Map
+	// order contains the keys of found in the order they were read
+	order []string
 }
 
 // NewReader returns a [Reader] which looks up environment variables from
@@ -59,6 +64,23 @@ func (r *Reader) Environ() (Map, error) {
 	return r.found, nil
 }
 
+// OrderedEnviron continues reading and scanning the internal [io.Reader] and
+// returns an [OrderedMap] of all found environment variables, in the order in
+// which they are defined, when either EOF is reached or an error has occurred.
+func (r *Reader) OrderedEnviron() (*OrderedMap, error) {
+	if _, _, err := r.scan(""); err != nil {
+		return nil, err
+	}
+
+	res := &OrderedMap{
+		keys: make([]string, len(r.order)),
+		vals: make(Map, len(r.found)),
+	}
+	copy(res.keys, r.order)
+	res.vals.MergeValues(r.found)
+	return res, nil
+}
+
 // scan continues scanning the internal [io.Reader] until either EOF is reached
 // or lookup is found. It will return the found value, a boolean indicating if
 // the lookup was found and an error if any.
@@ -73,6 +95,9 @@ func (r *Reader) scan(lookup string) (Value, bool, error) {
 			return "", false, err
 		}
 
+		if _, ok := r.found[env.Name]; !ok {
+			r.order = append(r.order, env.Name)
+		}
 		r.found[env.Name] = env.Value
 		if lookup != "" && lookup == env.Name {
 			return env.Value, true, nil
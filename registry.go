@@ -0,0 +1,142 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+)
+
+const ErrUnknownConverter errors.Msg = "unknown converter"
+
+// Converter is a named pair of funcs which (un)marshal the values of fields
+// that refer to the converter by name using the conv tag option.
+type Converter struct {
+	Marshal   rawconv.MarshalFunc
+	Unmarshal rawconv.UnmarshalFunc
+}
+
+// A Registry contains registered (un)marshal funcs for types, named
+// [Converter]s and the variants of interface types. Its (un)marshal funcs are
+// kept by a [rawconv.Marshaler] and [rawconv.Unmarshaler], which fall back to
+// the global funcs of the rawconv package. A Registry created with
+// [NewRegistry] falls back to the package-global Registry when neither of
+// those have a func for a type. It is used by a [Decoder], [Encoder] or
+// [Extractor] using [Decoder.WithRegistry], [Encoder.WithRegistry] or
+// [Extractor.WithRegistry].
+type Registry struct {
+	parent      *Registry
+	marshaler   rawconv.Marshaler
+	unmarshaler rawconv.Unmarshaler
+	converters  map[string]Converter
+	variants    map[reflect.Type]*variants
+}
+
+// global is the package-global [Registry].
+var global = newRegistry(nil)
+
+// NewRegistry returns a new [Registry] which falls back to the package-global
+// [Registry].
+func NewRegistry() *Registry { return newRegistry(global) }
+
+func newRegistry(parent *Registry) *Registry {
+	r := &Registry{parent: parent}
+	// the Marshaler and Unmarshaler interfaces of this package take
+	// precedence over those of the encoding package, which are registered
+	// with rawconv's global funcs
+	r.RegisterMarshalFunc(marshalerType, marshalEnv)
+	r.RegisterUnmarshalFunc(unmarshalerType, unmarshalEnv)
+	return r
+}
+
+const panicNilRegistry = "env: Registry must not be nil"
+
+// registryOrGlobal returns r, or the package-global [Registry] when r is nil.
+func registryOrGlobal(r *Registry) *Registry {
+	if r == nil {
+		return global
+	}
+	return r
+}
+
+// RegisterMarshalFunc registers the [rawconv.MarshalFunc] for typ. It panics
+// when typ is not supported by [rawconv.Marshaler.Register].
+func (r *Registry) RegisterMarshalFunc(typ reflect.Type, fn rawconv.MarshalFunc) *Registry {
+	r.marshaler.Register(typ, fn)
+	return r
+}
+
+// RegisterUnmarshalFunc registers the [rawconv.UnmarshalFunc] for typ. It
+// panics when typ is not supported by [rawconv.Unmarshaler.Register].
+func (r *Registry) RegisterUnmarshalFunc(typ reflect.Type, fn rawconv.UnmarshalFunc) *Registry {
+	r.unmarshaler.Register(typ, fn)
+	return r
+}
+
+// RegisterConverter registers the [Converter] with name. Fields refer to it
+// using the conv tag option, e.g. `env:"KEY,conv=name"`.
+func (r *Registry) RegisterConverter(name string, conv Converter) *Registry {
+	if r.converters == nil {
+		r.converters = make(map[string]Converter, 2)
+	}
+	r.converters[name] = conv
+	return r
+}
+
+// MarshalFunc returns the [rawconv.MarshalFunc] for typ, or nil if there is
+// none registered.
+func (r *Registry) MarshalFunc(typ reflect.Type) rawconv.MarshalFunc {
+	if fn := r.marshaler.Func(typ); fn != nil || r.parent == nil {
+		return fn
+	}
+	return r.parent.MarshalFunc(typ)
+}
+
+// UnmarshalFunc returns the [rawconv.UnmarshalFunc] for typ, or nil if there
+// is none registered.
+func (r *Registry) UnmarshalFunc(typ reflect.Type) rawconv.UnmarshalFunc {
+	if fn := r.unmarshaler.Func(typ); fn != nil || r.parent == nil {
+		return fn
+	}
+	return r.parent.UnmarshalFunc(typ)
+}
+
+// Converter returns the [Converter] registered with name.
+func (r *Registry) Converter(name string) (Converter, bool) {
+	for reg := r; reg != nil; reg = reg.parent {
+		if conv, ok := reg.converters[name]; ok {
+			return conv, true
+		}
+	}
+	return Converter{}, false
+}
+
+func (r *Registry) knownByMarshaler(typ reflect.Type) bool {
+	return r.MarshalFunc(typ) != nil
+}
+
+func (r *Registry) knownByUnmarshaler(typ reflect.Type) bool {
+	return r.UnmarshalFunc(typ) != nil
+}
+
+// marshal rv using a registered [rawconv.MarshalFunc], or the rawconv
+// package's default behavior when there is none.
+func (r *Registry) marshal(rv reflect.Value) (Value, error) {
+	if fn := r.MarshalFunc(rv.Type()); fn != nil {
+		return fn.Exec(rv)
+	}
+	return r.marshaler.Marshal(rv)
+}
+
+// unmarshal val into rv using a registered [rawconv.UnmarshalFunc], or the
+// rawconv package's default behavior when there is none.
+func (r *Registry) unmarshal(val Value, rv reflect.Value) error {
+	if fn := r.UnmarshalFunc(rv.Type()); fn != nil {
+		return fn.Exec(val, rv)
+	}
+	return r.unmarshaler.Unmarshal(val, rv)
+}
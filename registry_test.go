@@ -0,0 +1,130 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func secondsRegistry() *Registry {
+	durationType := reflect.TypeOf(time.Duration(0))
+	return NewRegistry().
+		RegisterUnmarshalFunc(durationType, func(val rawconv.Value, dest any) error {
+			n, err := strconv.Atoi(val.String())
+			*dest.(*time.Duration) = time.Duration(n) * time.Second
+			return err
+		}).
+		RegisterMarshalFunc(durationType, func(v any) (string, error) {
+			return strconv.Itoa(int(v.(time.Duration) / time.Second)), nil
+		})
+}
+
+func upperConverter() Converter {
+	return Converter{
+		Marshal: func(v any) (string, error) {
+			return strings.ToLower(v.(string)), nil
+		},
+		Unmarshal: func(val rawconv.Value, dest any) error {
+			*dest.(*string) = strings.ToUpper(val.String())
+			return nil
+		},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration
+		Name    string `env:",conv=upper"`
+	}
+
+	src := Map{"TIMEOUT": "30", "NAME": "foo"}
+	reg := secondsRegistry().RegisterConverter("upper", upperConverter())
+
+	t.Run("decode", func(t *testing.T) {
+		var have Config
+		assert.NoError(t, NewDecoder(src).WithRegistry(reg).Decode(&have))
+		assert.Exactly(t, Config{Timeout: 30 * time.Second, Name: "FOO"}, have)
+	})
+	t.Run("encode", func(t *testing.T) {
+		var buf strings.Builder
+		enc := NewEncoder(&buf).WithRegistry(reg)
+		enc.TakeValues = true
+		assert.NoError(t, enc.Encode(Config{Timeout: time.Minute, Name: "FOO"}))
+		assert.Equal(t, "TIMEOUT=60\nNAME=foo\n", buf.String())
+	})
+	t.Run("extract", func(t *testing.T) {
+		type defaults struct {
+			Timeout time.Duration `default:"10"`
+		}
+		have, err := NewExtractor().WithRegistry(reg).Extract(defaults{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"TIMEOUT": 10 * time.Second}, have)
+	})
+	t.Run("global is unaffected", func(t *testing.T) {
+		var have struct{ Timeout time.Duration }
+		err := NewDecoder(src).Decode(&have)
+		assert.Error(t, err)
+	})
+	t.Run("unknown converter", func(t *testing.T) {
+		var have Config
+		err := NewDecoder(src).WithRegistry(secondsRegistry()).Decode(&have)
+		assert.ErrorIs(t, err, ErrUnknownConverter)
+		assert.ErrorContains(t, err, "upper")
+	})
+	t.Run("fallback to global", func(t *testing.T) {
+		var have struct {
+			Fixture unmarshalFixture
+		}
+		assert.NoError(t, NewDecoder(Map{"FIXTURE": "foo"}).WithRegistry(NewRegistry()).Decode(&have))
+		assert.Equal(t, "foo", have.Fixture.val)
+	})
+	t.Run("nil", func(t *testing.T) {
+		assert.PanicsWithValue(t, panicNilRegistry, func() {
+			NewDecoder(src).WithRegistry(nil)
+		})
+	})
+}
+
+type unmarshalFixture struct{ val string }
+
+func (u *unmarshalFixture) UnmarshalEnv(b []byte) error {
+	u.val = string(b)
+	return nil
+}
+
+func TestRegistry_UnmarshalFunc(t *testing.T) {
+	reg := NewRegistry()
+	fixtureType := reflect.TypeOf(unmarshalFixture{})
+
+	t.Run("global interface", func(t *testing.T) {
+		assert.NotNil(t, reg.UnmarshalFunc(fixtureType))
+		assert.NotNil(t, reg.UnmarshalFunc(reflect.PtrTo(fixtureType)))
+	})
+	t.Run("rawconv fallback", func(t *testing.T) {
+		assert.NotNil(t, reg.UnmarshalFunc(reflect.TypeOf(time.Duration(0))))
+	})
+	t.Run("registered", func(t *testing.T) {
+		reg := NewRegistry()
+		stringType := reflect.TypeOf("")
+		reg.RegisterUnmarshalFunc(stringType, upperConverter().Unmarshal)
+		assert.NotNil(t, reg.UnmarshalFunc(stringType))
+		assert.Nil(t, GetUnmarshalFunc(stringType))
+	})
+	t.Run("unsupported kind", func(t *testing.T) {
+		assert.Panics(t, func() {
+			reg.RegisterUnmarshalFunc(reflect.TypeOf(func() {}), nil)
+		})
+		assert.Panics(t, func() {
+			reg.RegisterUnmarshalFunc(reflect.TypeOf([]byte(nil)), nil)
+		})
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import "strings"
+
+// OriginLookupper is a [Lookupper] which is able to describe the origin of
+// the values it looks up, e.g. the file they are read from.
+type OriginLookupper interface {
+	Lookupper
+	// LookupOrigin returns a description of where the [Value] of the
+	// environment variable named by the key originates from. It must return
+	// an [ErrNotFound] error if the key is not present.
+	LookupOrigin(key string) (string, error)
+}
+
+// Source indicates where the value of a decoded field comes from.
+type Source uint8
+
+const (
+	// SourceNone indicates the field did not resolve to a value.
+	SourceNone Source = iota
+	// SourceEnviron indicates the value comes from the environment variable.
+	SourceEnviron
+	// SourceDefault indicates the value is the field's default value.
+	SourceDefault
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceEnviron:
+		return "environ"
+	case SourceDefault:
+		return "default"
+	default:
+		return "none"
+	}
+}
+
+// Report is returned by [Decoder.DecodeReport] and contains a [FieldReport]
+// for each decoded field, in the order they were decoded.
+type Report []FieldReport
+
+// Lookup returns the [FieldReport] of the field with the provided path, e.g.
+// Config.DB.Port.
+func (r Report) Lookup(field string) (FieldReport, bool) {
+	for _, fr := range r {
+		if fr.Field == field {
+			return fr, true
+		}
+	}
+	return FieldReport{}, false
+}
+
+// IsSet indicates if the environment variable of the field with the provided
+// path is explicitly set, possibly to an empty value.
+func (r Report) IsSet(field string) bool {
+	fr, ok := r.Lookup(field)
+	return ok && fr.Set
+}
+
+// FieldReport describes how a single field is decoded.
+type FieldReport struct {
+	// Name of the environment variable.
+	Name string
+	// Alias is the alternate or deprecated name of the environment variable
+	// which is found instead of Name.
+	Alias string
+	// Field is the path to the struct field, e.g. Config.DB.Port.
+	Field string
+	// Set indicates the environment variable is explicitly set, possibly to
+	// an empty value.
+	Set bool
+	// Source indicates where the field's value comes from.
+	Source Source
+	// Expanded indicates the raw value contains references to other
+	// environment variables which are expanded.
+	Expanded bool
+	// Lookupper is the [Lookupper] that supplied the raw value, it is nil when
+	// Source is not [SourceEnviron]. When the [Decoder] looks up values from a
+	// chain of [Lookupper]s, it is the first one in the chain that contains
+	// the environment variable.
+	Lookupper Lookupper
+	// Origin is an optional description of where the raw value originates
+	// from, as provided by an [OriginLookupper].
+	Origin string
+	// Secret indicates the field is a secret, its Raw and Value are
+	// [Redacted].
+	Secret bool
+	// Raw is the value, or default value, before any references are
+	// expanded.
+	Raw Value
+	// Value is the final value that is decoded into the field. It is
+	// [Redacted] when Raw references the environment variable of a secret
+	// field, as the expanded value then contains the secret. References to
+	// secret environment variables which are not decoded into a secret field
+	// cannot be detected.
+	Value Value
+}
+
+func (fr *FieldReport) redact() {
+	fr.Secret = true
+	fr.Raw = Value(redact(fr.Raw.String()))
+	fr.Value = Value(redact(fr.Value.String()))
+}
+
+// redactSecretRefs redacts the Value of each expanded field of which the raw
+// value references the environment variable of a secret field, or of another
+// field that is redacted this way.
+func (r Report) redactSecretRefs() {
+	secrets := make(map[string]struct{})
+	for _, fr := range r {
+		if fr.Secret {
+			secrets[fr.Name] = struct{}{}
+			if fr.Alias != "" {
+				secrets[fr.Alias] = struct{}{}
+			}
+		}
+	}
+
+	redacted := make([]bool, len(r))
+	for changed := len(secrets) != 0; changed; {
+		changed = false
+		for i := range r {
+			fr := &r[i]
+			if fr.Secret || redacted[i] || !fr.Expanded || !referencesAny(fr.Raw, secrets) {
+				continue
+			}
+
+			fr.Value = Value(redact(fr.Value.String()))
+			redacted[i] = true
+			secrets[fr.Name] = struct{}{}
+			if fr.Alias != "" {
+				secrets[fr.Alias] = struct{}{}
+			}
+			changed = true
+		}
+	}
+}
+
+// referencesAny indicates if val references any of names.
+func referencesAny(val Value, names map[string]struct{}) bool {
+	for _, m := range matcher.FindAllStringSubmatch(val.String(), -1) {
+		name := m[1]
+		if name[0] == '{' {
+			name = name[1 : len(name)-1]
+			if i := strings.Index(name, ":-"); i >= 0 {
+				name = name[:i]
+			}
+		}
+		if _, ok := names[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// newFieldReport creates a [FieldReport] for field, which resolved to val
+// from source. The environment variable is looked up using key.
+func newFieldReport(l Lookupper, field fieldInfo, key string, set bool, source Source, val Value) FieldReport {
+	fr := FieldReport{
+		Name:   field.Tag.Name,
+		Field:  field.Path,
+		Set:    set,
+		Source: source,
+		Raw:    val,
+		Value:  val,
+	}
+	if key != fr.Name {
+		fr.Alias = key
+	}
+	switch source {
+	case SourceEnviron:
+		fr.Raw, fr.Lookupper, fr.Origin = traceLookup(l, key)
+		fr.Expanded = fr.Raw != val
+	case SourceDefault:
+		fr.Raw = Value(field.Tag.Default)
+		fr.Expanded = fr.Raw != val
+	}
+	return fr
+}
+
+// traceLookup looks up key and returns its raw value, the [Lookupper] that
+// supplied it and the optional origin of the value. Any [Replacer] is
+// unwrapped and chains are traversed to find the actual source.
+func traceLookup(l Lookupper, key string) (Value, Lookupper, string) {
+	switch x := l.(type) {
+	case *Replacer:
+		return traceLookup(x.lookupper, key)
+
+	case chainLookupper:
+		for _, c := range x {
+			if _, err := c.Lookup(key); err == nil {
+				return traceLookup(c, key)
+			}
+		}
+		return "", nil, ""
+
+	default:
+		val, _ := l.Lookup(key)
+
+		var origin string
+		if ol, ok := l.(OriginLookupper); ok {
+			origin, _ = ol.LookupOrigin(key)
+		}
+		return val, l, origin
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/go-pogo/env/envtag"
+	"github.com/go-pogo/errors"
+)
+
+// Redacted is the replacement of non-empty secret values.
+const Redacted = "******"
+
+var secretType = reflect.TypeOf(Secret(""))
+
+// Secret is a string value which is redacted when formatted using the fmt
+// package, encoded by an [Encoder], extracted by an [Extractor] or mentioned
+// in errors. Use [Secret.Reveal] to get the actual value.
+type Secret string
+
+// Reveal returns the actual, unredacted value of the [Secret].
+func (s Secret) Reveal() string { return string(s) }
+
+// String returns [Redacted], or an empty string when the [Secret] is empty.
+func (s Secret) String() string { return redact(string(s)) }
+
+func (s Secret) GoString() string { return `env.Secret("` + s.String() + `")` }
+
+// Format implements [fmt.Formatter] and writes the redacted value for any
+// verb.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		_, _ = io.WriteString(f, s.GoString())
+		return
+	}
+	_, _ = io.WriteString(f, s.String())
+}
+
+func redact(str string) string {
+	if str == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// isSecret indicates if the value of a field of type typ with tag should be
+// treated as a secret.
+func isSecret(typ reflect.Type, tag envtag.Tag) bool {
+	if tag.Secret {
+		return true
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ == secretType
+}
+
+// secretError replaces the error of a [DecodeError] of a secret, as its
+// message, or the message of any error it wraps, may contain the secret value,
+// e.g. strconv.ParseInt: parsing "p4ssw0rd": invalid syntax. It does not
+// unwrap to its cause, but does match it using [errors.Is].
+type secretError struct {
+	cause error
+}
+
+func (e *secretError) Is(target error) bool { return errors.Is(e.cause, target) }
+
+func (e *secretError) Error() string {
+	var valErr *ValidationError
+	if errors.As(e.cause, &valErr) {
+		// the rule and its param do not contain the value
+		return (&ValidationError{Rule: valErr.Rule, Param: valErr.Param}).Error()
+	}
+	return "invalid secret value"
+}
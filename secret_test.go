@@ -0,0 +1,158 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/go-pogo/rawconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecret(t *testing.T) {
+	s := Secret("p4ssw0rd")
+	assert.Equal(t, "p4ssw0rd", s.Reveal())
+	assert.Equal(t, Redacted, s.String())
+
+	for _, format := range []string{"%s", "%v", "%q", "%x", "%+v"} {
+		assert.Equal(t, Redacted, fmt.Sprintf(format, s), format)
+	}
+	assert.Equal(t, `env.Secret("******")`, fmt.Sprintf("%#v", s))
+	assert.Equal(t, "{******}", fmt.Sprint(struct{ Password Secret }{s}))
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "", Secret("").String())
+	})
+}
+
+type secretConfig struct {
+	User     string
+	Password Secret
+	Token    string `env:",secret"`
+	Pin      int    `env:",secret"`
+}
+
+func TestSecret_decode(t *testing.T) {
+	var have secretConfig
+	assert.NoError(t, NewDecoder(Map{
+		"USER":     "root",
+		"PASSWORD": "p4ssw0rd",
+		"TOKEN":    "t0k3n",
+	}).Decode(&have))
+	assert.Equal(t, secretConfig{User: "root", Password: "p4ssw0rd", Token: "t0k3n"}, have)
+
+	t.Run("error", func(t *testing.T) {
+		err := NewDecoder(Map{"PIN": "12ab"}).Decode(&have)
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, err, &decErr)
+		assert.True(t, decErr.Secret)
+		assert.Equal(t, Value("12ab"), decErr.Value)
+		assert.NotContains(t, err.Error(), "12ab")
+		assert.Contains(t, err.Error(), "PIN=******")
+		assert.NotContains(t, fmt.Sprintf("%v", err), "12ab")
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "12ab")
+		assert.NotContains(t, decErr.Err.Error(), "12ab")
+		assert.ErrorIs(t, err, rawconv.ErrParseFailure)
+	})
+	t.Run("validation error", func(t *testing.T) {
+		var have struct {
+			Pin string `env:",secret" validate:"oneof=1234 5678"`
+		}
+		err := NewDecoder(Map{"PIN": "hunter2"}).Decode(&have)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "hunter2")
+		assert.Contains(t, err.Error(), "failed validation rule `oneof=1234 5678`")
+	})
+	t.Run("expanded reference", func(t *testing.T) {
+		var have struct {
+			Password Secret `env:"PW"`
+			URL      string
+			Mirror   string
+			Host     string
+		}
+		report, err := NewDecoder(Map{
+			"PW":     "p4ssw0rd",
+			"URL":    "postgres://u:${PW}@h",
+			"MIRROR": "$URL",
+			"HOST":   "h:${PORT:-5432}",
+		}).WithOptions(DecodeOptions{ReplaceVars: true}).DecodeReport(&have)
+		assert.NoError(t, err)
+		assert.Equal(t, "postgres://u:p4ssw0rd@h", have.URL)
+
+		for _, field := range []string{"URL", "Mirror"} {
+			fr, _ := report.Lookup(field)
+			assert.False(t, fr.Secret, field)
+			assert.Equal(t, Value(Redacted), fr.Value, field)
+		}
+
+		fr, _ := report.Lookup("Host")
+		assert.Equal(t, Value("h:5432"), fr.Value)
+	})
+	t.Run("report", func(t *testing.T) {
+		var have secretConfig
+		report, err := NewDecoder(Map{"PASSWORD": "p4ssw0rd"}).DecodeReport(&have)
+		assert.NoError(t, err)
+
+		fr, _ := report.Lookup("secretConfig.Password")
+		assert.True(t, fr.Secret)
+		assert.Equal(t, Value(Redacted), fr.Raw)
+		assert.Equal(t, Value(Redacted), fr.Value)
+	})
+}
+
+func TestSecret_encode(t *testing.T) {
+	cfg := secretConfig{User: "root", Password: "p4ssw0rd", Token: "t0k3n"}
+
+	var buf strings.Builder
+	enc := NewEncoder(&buf)
+	enc.TakeValues = true
+	assert.NoError(t, enc.Encode(cfg))
+	assert.Equal(t, "USER=root\nPASSWORD=******\nTOKEN=******\nPIN=0\n", buf.String())
+
+	t.Run("reveal", func(t *testing.T) {
+		buf.Reset()
+		enc.RevealSecrets = true
+		assert.NoError(t, enc.Encode(cfg))
+		assert.Equal(t, "USER=root\nPASSWORD=p4ssw0rd\nTOKEN=t0k3n\nPIN=0\n", buf.String())
+	})
+	t.Run("named values", func(t *testing.T) {
+		buf.Reset()
+		assert.NoError(t, NewEncoder(&buf).Encode([]NamedValue{
+			{Name: "USER", Value: "root"},
+			{Name: "PASSWORD", Value: "p4ssw0rd", Secret: true},
+		}))
+		assert.Equal(t, "USER=root\nPASSWORD=******\n", buf.String())
+	})
+}
+
+func TestSecret_extract(t *testing.T) {
+	have, err := Extract(secretConfig{Password: "p4ssw0rd", Token: "t0k3n", Pin: 1234})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"USER":     "",
+		"PASSWORD": Secret("p4ssw0rd"),
+		"TOKEN":    Secret("t0k3n"),
+		"PIN":      Secret("1234"),
+	}, have)
+	assert.NotContains(t, fmt.Sprint(have), "t0k3n")
+}
+
+func TestNamedValue_GoString(t *testing.T) {
+	nv := NamedValue{Name: "PASSWORD", Value: "p4ssw0rd"}
+	assert.Equal(t, `env.NamedValue(PASSWORD="p4ssw0rd")`, fmt.Sprintf("%#v", nv))
+
+	nv.Secret = true
+	assert.Equal(t, `env.NamedValue(PASSWORD="******")`, fmt.Sprintf("%#v", nv))
+}
+
+func TestParseError_Error(t *testing.T) {
+	_, err := Parse(`PASSWORD="p4ssw0rd`)
+	assert.ErrorIs(t, err, ErrMissingEndQuote)
+	assert.Equal(t, "error while parsing `PASSWORD=******`", errors.Unembed(err).Error())
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package env
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogDeprecated returns a callback which can be used as
+// [DecodeOptions.OnDeprecated]. It logs a warning with logger each time a
+// deprecated environment variable name is used. When logger is nil,
+// [slog.Default] is used.
+func LogDeprecated(logger *slog.Logger) func(Deprecation) {
+	return func(dep Deprecation) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		l.LogAttrs(context.Background(), slog.LevelWarn,
+			"deprecated environment variable",
+			slog.String("name", dep.Name),
+			slog.String("replacement", dep.Replacement),
+			slog.String("field", dep.Field),
+		)
+	}
+}
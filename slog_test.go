@@ -0,0 +1,37 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+
+package env
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogDeprecated(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	LogDeprecated(logger)(Deprecation{
+		Name:        "OLD_PORT",
+		Replacement: "PORT",
+		Field:       "Config.Port",
+	})
+	assert.Equal(t,
+		"level=WARN msg=\"deprecated environment variable\" name=OLD_PORT replacement=PORT field=Config.Port\n",
+		buf.String(),
+	)
+}
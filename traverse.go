@@ -6,35 +6,82 @@ package env
 
 import (
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/go-pogo/env/envtag"
 )
 
-func typeKnownByUnmarshaler(typ reflect.Type) bool {
-	return unmarshaler.Func(typ) != nil
-}
-
-func typeKnownByMarshaler(typ reflect.Type) bool {
-	return marshaler.Func(typ) != nil
+// fieldInfo contains information about a struct field that is being handled
+// by a traverser.
+type fieldInfo struct {
+	reflect.StructField
+	Tag envtag.Tag
+	// Path is the path to the field, starting from the root struct, e.g.
+	// Config.DB.Port.
+	Path string
 }
 
 type traverser struct {
 	TagOptions
 
 	isKnownType func(reflect.Type) bool
-	handleField func(reflect.Value, envtag.Tag) error
+	handleField func(reflect.Value, fieldInfo) error
+	// afterStruct is optional and called after all fields of a (nested)
+	// struct are handled.
+	afterStruct func(reflect.Value, fieldInfo) error
+	// prepareSlice is optional and called for slice fields of which the
+	// elements are structs. It returns the slice of which each element is
+	// traversed using the field's name and the element's index as prefix. An
+	// invalid [reflect.Value] indicates the field should be skipped.
+	prepareSlice func(reflect.Value, fieldInfo) (reflect.Value, error)
+	// prepareMap is optional and called for map fields of which the elements
+	// are structs. It returns the map and its entries, each element is
+	// traversed using the field's name and the entry's name as prefix. An
+	// invalid [reflect.Value] indicates the field should be skipped.
+	prepareMap func(reflect.Value, fieldInfo) (reflect.Value, []mapEntry, error)
+	// prepareIface is optional and called for interface fields which have a
+	// discriminator. It returns the concrete value of which the struct is
+	// traversed using the field's name as prefix. An invalid [reflect.Value]
+	// indicates the field should be skipped.
+	prepareIface func(reflect.Value, fieldInfo) (reflect.Value, error)
+	// setElems indicates the traversed map elements and concrete values of
+	// interface fields should be set, which is required when decoding.
+	setElems bool
+	// nilPtr is optional and called for nil pointers to structs, with a
+	// function that traverses a newly allocated struct. It reports if the
+	// pointer should be set to the allocated struct. When nil, the allocated
+	// struct is traversed and the pointer remains nil.
+	nilPtr func(traverse func() error) (bool, error)
+}
+
+// mapEntry is an entry of a map field of which the elements are structs.
+type mapEntry struct {
+	// name of the entry, as used in the environment variables' names.
+	name string
+	key  reflect.Value
+}
+
+// sortMapEntries sorts entries by name, so map elements are always traversed
+// in the same order.
+func sortMapEntries(entries []mapEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
 }
 
 func (t *traverser) start(pv reflect.Value) error {
-	return t.traverse(pv, "", false)
+	typ := pv.Type()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return t.traverseStruct(pv, "", fieldInfo{Path: typ.Name()}, false)
 }
 
 const panicPtr = "env: ptr values should always be resolved; this is a bug!"
 
-func (t *traverser) traverse(pv reflect.Value, prefix string, include bool) error {
-	// todo: dit moet anders, in het geval van encode zou pv.Interface() wss. prima nil kunnen zijn
-	pv = indirect(pv)
-
+func (t *traverser) traverse(pv reflect.Value, prefix, path string, include bool) error {
 	pt := pv.Type()
 	for i, l := 0, pv.NumField(); i < l; i++ {
 		field, rv := pt.Field(i), pv.Field(i)
@@ -55,18 +102,46 @@ func (t *traverser) traverse(pv reflect.Value, prefix string, include bool) erro
 			continue
 		}
 
-		/*if t.isKnownType == nil {
-			if handled, err := t.handleField(rv, tag); err != nil {
+		fi := fieldInfo{
+			StructField: field,
+			Tag:         tag,
+			Path:        joinPath(path, field.Name),
+		}
+
+		if tag.Converter != "" {
+			// a named converter handles the field's value as a whole
+			if err := t.handleField(rv, fi); err != nil {
 				return err
-			} else if handled {
+			}
+			continue
+		}
+		if tag.Discriminator != "" && field.Type.Kind() == reflect.Interface {
+			if t.prepareIface == nil {
 				continue
 			}
-		} else*/if kind != reflect.Struct || t.isKnownType(rv.Type()) {
+			if err := t.traverseIface(rv, fi, include || tag.Include); err != nil {
+				return err
+			}
+			continue
+		}
+		if kind == reflect.Slice && t.prepareSlice != nil && t.hasStructElem(field.Type) {
+			if err := t.traverseSlice(rv, fi, include || tag.Include); err != nil {
+				return err
+			}
+			continue
+		}
+		if kind == reflect.Map && t.prepareMap != nil && t.hasStructElem(field.Type) {
+			if err := t.traverseMap(rv, fi, include || tag.Include); err != nil {
+				return err
+			}
+			continue
+		}
+		if kind != reflect.Struct || t.isKnownType(rv.Type()) {
 			// when the field is a struct and is a known type (of the global
 			// (un)marshaler) it means the Decoder/Encoder can handle the field
 			// after which we'll continue with the next field, without further
 			// traversing the struct's fields
-			if err := t.handleField(rv, tag); err != nil {
+			if err := t.handleField(rv, fi); err != nil {
 				return err
 			}
 			continue
@@ -79,13 +154,169 @@ func (t *traverser) traverse(pv reflect.Value, prefix string, include bool) erro
 			p = tag.Name
 		}
 
-		if err := t.traverse(rv, p, include || tag.Include); err != nil {
+		if err := t.traverseStruct(rv, p, fi, include || tag.Include); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traverseStruct traverses the fields of struct pv, after which afterStruct
+// is called. When pv is a nil pointer, a newly allocated struct is traversed
+// instead, see nilPtr.
+func (t *traverser) traverseStruct(pv reflect.Value, prefix string, field fieldInfo, include bool) error {
+	sv := pv
+	for sv.Kind() == reflect.Ptr {
+		if sv.IsNil() {
+			return t.traverseNilPtr(sv, prefix, field, include)
+		}
+		sv = sv.Elem()
+	}
+
+	if err := t.traverse(sv, prefix, field.Path, include); err != nil {
+		return err
+	}
+	if t.afterStruct != nil {
+		return t.afterStruct(pv, field)
+	}
+	return nil
+}
+
+func (t *traverser) traverseNilPtr(pv reflect.Value, prefix string, field fieldInfo, include bool) error {
+	ptr := reflect.New(pv.Type().Elem())
+	traverse := func() error {
+		return t.traverseStruct(ptr, prefix, field, include)
+	}
+	if t.nilPtr == nil {
+		return traverse()
+	}
+
+	set, err := t.nilPtr(traverse)
+	if set {
+		pv.Set(ptr)
+	}
+	return err
+}
+
+// hasStructElem indicates if typ is a slice or map of which the elements are
+// structs that are not a known type.
+func (t *traverser) hasStructElem(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	elem := typ.Elem()
+	return underlyingKind(elem) == reflect.Struct && !t.isKnownType(elem)
+}
+
+// traverseSlice traverses each struct element of the slice prepared by
+// prepareSlice, e.g. the fields of the second element of slice field
+// BACKENDS have prefix BACKENDS_1.
+func (t *traverser) traverseSlice(rv reflect.Value, field fieldInfo, include bool) error {
+	sv, err := t.prepareSlice(rv, field)
+	if err != nil || !sv.IsValid() {
+		return err
+	}
+
+	for i, l := 0, sv.Len(); i < l; i++ {
+		index := strconv.Itoa(i)
+		elem := field
+		elem.Tag.Name += "_" + index
+		elem.Path += "[" + index + "]"
+
+		if err = t.traverseStruct(sv.Index(i), elem.Tag.Name, elem, include); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traverseMap traverses each struct element of the map prepared by
+// prepareMap, e.g. the fields of the element with name PRIMARY of map field DB
+// have prefix DB_PRIMARY. The name of the entry is used as is, so it is not
+// altered by the [envtag.Normalizer], e.g. the fields of the element with name
+// primary have prefix DB_primary.
+func (t *traverser) traverseMap(rv reflect.Value, field fieldInfo, include bool) error {
+	mv, entries, err := t.prepareMap(rv, field)
+	if err != nil || !mv.IsValid() {
+		return err
+	}
+
+	normalizer := t.Normalizer
+	defer func() { t.Normalizer = normalizer }()
+
+	typ := mv.Type().Elem()
+	for _, entry := range entries {
+		el := reflect.New(typ).Elem()
+		if v := mv.MapIndex(entry.key); v.IsValid() {
+			el.Set(v)
+		}
+
+		elem := field
+		elem.Tag.Name += "_" + entry.name
+		elem.Path += "[" + entry.name + "]"
+
+		if normalizer != nil {
+			t.Normalizer = entryNormalizer{
+				Normalizer: normalizer,
+				prefix:     elem.Tag.Name,
+			}
+		}
+		if err = t.traverseStruct(el, elem.Tag.Name, elem, include); err != nil {
 			return err
 		}
+		if t.setElems {
+			mv.SetMapIndex(entry.key, el)
+		}
+	}
+	return nil
+}
+
+// entryNormalizer wraps an [envtag.Normalizer] and normalizes the names of
+// the fields of a map element, without altering prefix, which contains the name
+// of the map entry.
+type entryNormalizer struct {
+	envtag.Normalizer
+	prefix string
+}
+
+func (n entryNormalizer) Normalize(fieldName, prefix string) string {
+	if prefix == n.prefix {
+		return prefix + "_" + n.Normalizer.Normalize(fieldName, "")
+	}
+	if strings.HasPrefix(prefix, n.prefix+"_") {
+		return n.prefix + "_" + n.Normalizer.Normalize(fieldName, prefix[len(n.prefix)+1:])
+	}
+	return n.Normalizer.Normalize(fieldName, prefix)
+}
+
+// traverseIface traverses the struct of the concrete value of interface field
+// rv, which is prepared by prepareIface, e.g. the fields of the variant of
+// interface field STORAGE have prefix STORAGE.
+func (t *traverser) traverseIface(rv reflect.Value, field fieldInfo, include bool) error {
+	el, err := t.prepareIface(rv, field)
+	if err != nil || !el.IsValid() {
+		return err
+	}
+
+	// copy the concrete value, so it is addressable
+	cv := reflect.New(el.Type()).Elem()
+	cv.Set(el)
+	if err = t.traverseStruct(cv, field.Tag.Name, field, include); err != nil {
+		return err
+	}
+	if t.setElems && !isNilPtr(cv) {
+		rv.Set(cv)
 	}
 	return nil
 }
 
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
 // underlyingKind resolves the underlying reflect.Kind of typ.
 func underlyingKind(typ reflect.Type) reflect.Kind {
 	if k := typ.Kind(); k != reflect.Ptr {
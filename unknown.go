@@ -0,0 +1,125 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrUnknownVar errors.Msg = "unknown environment variable"
+
+// UnknownVar describes an environment variable which is not consumed by any
+// of the fields of the struct that is decoded.
+type UnknownVar struct {
+	// Name of the environment variable.
+	Name string
+	// Suggestion is the name of a known environment variable which is similar
+	// to Name, or empty when there is none.
+	Suggestion string
+}
+
+// UnknownError is returned by [Decoder.Decode] when unknown environment
+// variables are disallowed and one or more are found.
+type UnknownError struct {
+	Vars []UnknownVar
+}
+
+// Is returns true when target is [ErrUnknownVar].
+func (e *UnknownError) Is(target error) bool { return target == ErrUnknownVar }
+
+func (e *UnknownError) Error() string {
+	var buf strings.Builder
+	buf.WriteString(ErrUnknownVar.String())
+	if len(e.Vars) > 1 {
+		buf.WriteRune('s')
+	}
+	buf.WriteString(": ")
+	for i, v := range e.Vars {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(v.Name)
+		if v.Suggestion != "" {
+			buf.WriteString(" (did you mean ")
+			buf.WriteString(v.Suggestion)
+			buf.WriteString("?)")
+		}
+	}
+	return buf.String()
+}
+
+// unknownVars returns an [UnknownVar] for each of the keys that is not known,
+// sorted by name.
+func unknownVars(keys []string, known map[string]struct{}) []UnknownVar {
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.Strings(keys)
+
+	var res []UnknownVar
+	for _, key := range keys {
+		if _, ok := known[key]; ok {
+			continue
+		}
+		res = append(res, UnknownVar{
+			Name:       key,
+			Suggestion: suggest(key, names),
+		})
+	}
+	return res
+}
+
+// suggest returns the name from names which is most similar to key, based on
+// their edit distance. It returns an empty string when none of the names are
+// similar enough.
+func suggest(key string, names []string) string {
+	// allow roughly one typo per four characters, with a minimum of two
+	maxDist := len(key) / 4
+	if maxDist < 2 {
+		maxDist = 2
+	}
+
+	var res string
+	for _, name := range names {
+		if dist := levenshtein(key, name); dist <= maxDist {
+			res, maxDist = name, dist-1
+		}
+	}
+	return res
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = prev[j-1] + cost
+			if n := prev[j] + 1; n < curr[j] {
+				curr[j] = n
+			}
+			if n := curr[j-1] + 1; n < curr[j] {
+				curr[j] = n
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want int
+	}{
+		"equal":      {a: "FOO", b: "FOO", want: 0},
+		"empty":      {a: "", b: "FOO", want: 3},
+		"insert":     {a: "DATABSE_URL", b: "DATABASE_URL", want: 1},
+		"substitute": {a: "PORT", b: "PART", want: 1},
+		"mixed":      {a: "kitten", b: "sitting", want: 3},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, levenshtein(tc.a, tc.b))
+			assert.Equal(t, tc.want, levenshtein(tc.b, tc.a))
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"DB_HOST", "DB_PORT", "DATABASE_URL"}
+	assert.Equal(t, "DATABASE_URL", suggest("DATABSE_URL", names))
+	assert.Equal(t, "DB_PORT", suggest("DB_PROT", names))
+	assert.Equal(t, "", suggest("SOMETHING_ELSE", names))
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrValidationFailed errors.Msg = "validation failed"
+	ErrUnknownRule      errors.Msg = "unknown validation rule"
+	ErrInvalidRuleParam errors.Msg = "invalid validation rule parameter"
+)
+
+// Validator is the interface implemented by (nested) config structs that can
+// validate themselves. [Decoder.Decode] calls Validate after all fields of the
+// struct are decoded and validated.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError is the error of a [DecodeError] when a decoded value does not
+// pass a validation rule.
+type ValidationError struct {
+	// Rule is the name of the validation rule, e.g. max.
+	Rule string
+	// Param is the optional parameter of the rule, e.g. 65535.
+	Param string
+	// Err is an optional underlying error.
+	Err error
+}
+
+// Is returns true when target is [ErrValidationFailed].
+func (e *ValidationError) Is(target error) bool { return target == ErrValidationFailed }
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+func (e *ValidationError) Error() string {
+	str := "failed validation rule `" + e.Rule
+	if e.Param != "" {
+		str += "=" + e.Param
+	}
+	str += "`"
+	if e.Err != nil {
+		str += ": " + e.Err.Error()
+	}
+	return str
+}
+
+type validationRule struct {
+	name  string
+	param string
+}
+
+// parseValidationRules parses a comma separated string of rules. Because
+// regular expressions may contain commas, the regex rule consumes the
+// remainder of the string and should therefore be the last rule.
+func parseValidationRules(str string) []validationRule {
+	var rules []validationRule
+	for str != "" {
+		var part string
+		if strings.HasPrefix(str, "regex=") {
+			part, str = str, ""
+		} else if i := strings.IndexRune(str, ','); i >= 0 {
+			part, str = str[:i], str[i+1:]
+		} else {
+			part, str = str, ""
+		}
+
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, param, _ := strings.Cut(part, "=")
+		rules = append(rules, validationRule{name: name, param: param})
+	}
+	return rules
+}
+
+// validate rv against the rules in str.
+func validate(reg *Registry, rv reflect.Value, str string) error {
+	for _, rule := range parseValidationRules(str) {
+		if err := rule.validate(reg, rv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r validationRule) validate(reg *Registry, rv reflect.Value) error {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			if r.name == "nonempty" {
+				return r.fail(nil)
+			}
+			// there is nothing to validate
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch r.name {
+	case "nonempty":
+		if rv.IsZero() || (hasLen(rv) && rv.Len() == 0) {
+			return r.fail(nil)
+		}
+		return nil
+
+	case "min", "max":
+		c, err := r.compare(reg, rv)
+		if err != nil {
+			return err
+		}
+		if (r.name == "min" && c < 0) || (r.name == "max" && c > 0) {
+			return r.fail(nil)
+		}
+		return nil
+
+	case "oneof":
+		str, err := reg.marshal(rv)
+		if err != nil {
+			return r.fail(err)
+		}
+		for _, opt := range strings.Fields(r.param) {
+			if opt == str.String() {
+				return nil
+			}
+		}
+		return r.fail(nil)
+
+	case "regex":
+		re, err := regexp.Compile(r.param)
+		if err != nil {
+			return r.invalidParam(err)
+		}
+		str, err := reg.marshal(rv)
+		if err != nil {
+			return r.fail(err)
+		}
+		if !re.MatchString(str.String()) {
+			return r.fail(nil)
+		}
+		return nil
+
+	case "url":
+		str, err := reg.marshal(rv)
+		if err != nil {
+			return r.fail(err)
+		}
+		if str == "" {
+			return nil
+		}
+		u, err := url.Parse(str.String())
+		if err != nil {
+			return r.fail(err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return r.fail(nil)
+		}
+		return nil
+
+	case "hostport":
+		str, err := reg.marshal(rv)
+		if err != nil {
+			return r.fail(err)
+		}
+		if str == "" {
+			return nil
+		}
+		_, port, err := net.SplitHostPort(str.String())
+		if err != nil {
+			return r.fail(err)
+		}
+		if _, err = strconv.ParseUint(port, 10, 16); err != nil {
+			return r.fail(err)
+		}
+		return nil
+
+	default:
+		return errors.WithStack(&ValidationError{
+			Rule:  r.name,
+			Param: r.param,
+			Err:   ErrUnknownRule,
+		})
+	}
+}
+
+func (r validationRule) fail(err error) error {
+	return errors.WithStack(&ValidationError{
+		Rule:  r.name,
+		Param: r.param,
+		Err:   err,
+	})
+}
+
+// compare rv with the rule's param. Numeric values are compared with the
+// param unmarshaled to the same type, values with a length (strings, slices,
+// maps etc.) have their length compared with the param.
+func (r validationRule) compare(reg *Registry, rv reflect.Value) (int, error) {
+	if hasLen(rv) {
+		n, err := strconv.Atoi(r.param)
+		if err != nil {
+			return 0, r.invalidParam(err)
+		}
+		return compare(rv.Len(), n), nil
+	}
+
+	param := reflect.New(rv.Type()).Elem()
+	if err := reg.unmarshal(Value(r.param), param); err != nil {
+		return 0, r.invalidParam(err)
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compare(rv.Int(), param.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compare(rv.Uint(), param.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		return compare(rv.Float(), param.Float()), nil
+
+	default:
+		return 0, r.invalidParam(nil)
+	}
+}
+
+func (r validationRule) invalidParam(err error) error {
+	if err == nil {
+		err = ErrInvalidRuleParam
+	} else {
+		err = errors.Wrap(err, ErrInvalidRuleParam)
+	}
+	return errors.WithStack(&ValidationError{
+		Rule:  r.name,
+		Param: r.param,
+		Err:   err,
+	})
+}
+
+func hasLen(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+func compare[T int | int64 | uint64 | float64](a, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// callValidator calls the Validate method of rv when it, or a pointer to it,
+// implements [Validator].
+func callValidator(rv reflect.Value) error {
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+		rv = rv.Addr()
+	}
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+	if v, ok := rv.Interface().(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
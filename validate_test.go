@@ -0,0 +1,175 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValidationRules(t *testing.T) {
+	assert.Equal(t, []validationRule{
+		{name: "nonempty"},
+		{name: "min", param: "1"},
+		{name: "oneof", param: "a b"},
+		{name: "regex", param: "^[a-z]{1,3}$"},
+	}, parseValidationRules("nonempty, min=1,,oneof=a b,regex=^[a-z]{1,3}$"))
+}
+
+func TestValidate(t *testing.T) {
+	urlValue, _ := url.Parse("https://example.com")
+
+	tests := map[string]struct {
+		value   any
+		rules   string
+		wantErr bool
+	}{
+		"min int":             {value: 1, rules: "min=1"},
+		"min int fail":        {value: 0, rules: "min=1", wantErr: true},
+		"max uint":            {value: uint16(8080), rules: "max=65535"},
+		"max float fail":      {value: 1.5, rules: "max=1", wantErr: true},
+		"min duration":        {value: time.Minute, rules: "min=1s"},
+		"max duration fail":   {value: time.Minute, rules: "max=1s", wantErr: true},
+		"min string length":   {value: "foo", rules: "min=3,max=3"},
+		"max slice length":    {value: []string{"a", "b"}, rules: "max=1", wantErr: true},
+		"min invalid param":   {value: 1, rules: "min=one", wantErr: true},
+		"oneof":               {value: "info", rules: "oneof=debug info warn"},
+		"oneof fail":          {value: "trace", rules: "oneof=debug info warn", wantErr: true},
+		"regex":               {value: "abc", rules: "regex=^[a-z]{1,3}$"},
+		"regex fail":          {value: "abcd", rules: "regex=^[a-z]{1,3}$", wantErr: true},
+		"regex invalid":       {value: "abc", rules: "regex=[", wantErr: true},
+		"url":                 {value: "https://example.com/path", rules: "url"},
+		"url type":            {value: urlValue, rules: "url"},
+		"url fail":            {value: "example.com", rules: "url", wantErr: true},
+		"url empty":           {value: "", rules: "url"},
+		"hostport":            {value: "localhost:8080", rules: "hostport"},
+		"hostport empty host": {value: ":8080", rules: "hostport"},
+		"hostport fail":       {value: "localhost", rules: "hostport", wantErr: true},
+		"hostport bad port":   {value: "localhost:99999", rules: "hostport", wantErr: true},
+		"nonempty":            {value: "foo", rules: "nonempty"},
+		"nonempty fail":       {value: "", rules: "nonempty", wantErr: true},
+		"nonempty nil ptr":    {value: (*url.URL)(nil), rules: "nonempty", wantErr: true},
+		"nonempty empty map":  {value: map[string]int{}, rules: "nonempty", wantErr: true},
+		"unknown rule":        {value: "", rules: "nope", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validate(global, reflect.ValueOf(tc.value), tc.rules)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrValidationFailed)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type validatedConfig struct {
+	Port   int    `validate:"min=1,max=65535"`
+	Level  string `default:"info" validate:"oneof=debug info warn"`
+	Nested validatedNested
+}
+
+func (c validatedConfig) Validate() error {
+	if c.Port == 8080 {
+		return errors.New("port 8080 is reserved")
+	}
+	return nil
+}
+
+type validatedNested struct {
+	Addr string `validate:"hostport"`
+}
+
+func (n *validatedNested) Validate() error {
+	if strings.HasPrefix(n.Addr, "0.0.0.0") {
+		return errors.New("addr must not bind to all interfaces")
+	}
+	return nil
+}
+
+func TestDecoder_Decode_validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		var have validatedConfig
+		dec := NewDecoder(Map{"PORT": "80", "NESTED_ADDR": "localhost:80"})
+		assert.NoError(t, dec.Decode(&have))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var have validatedConfig
+		dec := NewDecoder(Map{
+			"PORT":        "70000",
+			"LEVEL":       "trace",
+			"NESTED_ADDR": "nope",
+		})
+
+		err := dec.Decode(&have)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+
+		var multiErr errors.MultiError
+		assert.ErrorAs(t, err, &multiErr)
+
+		errs := multiErr.Unwrap()
+		assert.Len(t, errs, 3)
+
+		var keys []string
+		for _, err = range errs {
+			var decErr *DecodeError
+			assert.ErrorAs(t, err, &decErr)
+			keys = append(keys, decErr.Key)
+		}
+		assert.Exactly(t, []string{"PORT", "LEVEL", "NESTED_ADDR"}, keys)
+	})
+	t.Run("validator", func(t *testing.T) {
+		var have validatedConfig
+		dec := NewDecoder(Map{"PORT": "8080", "NESTED_ADDR": "0.0.0.0:80"})
+
+		err := dec.Decode(&have)
+
+		var multiErr errors.MultiError
+		assert.ErrorAs(t, err, &multiErr)
+
+		errs := multiErr.Unwrap()
+		assert.Len(t, errs, 2)
+		assert.ErrorContains(t, errs[0], "addr must not bind to all interfaces")
+		assert.ErrorContains(t, errs[1], "port 8080 is reserved")
+
+		var decErr *DecodeError
+		assert.ErrorAs(t, errs[0], &decErr)
+		assert.Equal(t, "NESTED", decErr.Key)
+		assert.Equal(t, "validatedConfig.Nested", decErr.Field)
+	})
+	t.Run("unset", func(t *testing.T) {
+		type Config struct {
+			Name    string `validate:"nonempty"`
+			Workers int    `validate:"min=1"`
+			Empty   string `env:",allowempty" validate:"nonempty"`
+		}
+
+		var have Config
+		err := NewDecoder(Map{"EMPTY": ""}).Decode(&have)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+
+		var multiErr errors.MultiError
+		assert.ErrorAs(t, err, &multiErr)
+
+		var keys []string
+		for _, err = range multiErr.Unwrap() {
+			var valErr *ValidationError
+			assert.ErrorAs(t, err, &valErr)
+
+			var decErr *DecodeError
+			assert.ErrorAs(t, err, &decErr)
+			keys = append(keys, decErr.Key+":"+valErr.Rule)
+		}
+		assert.Exactly(t, []string{"NAME:nonempty", "WORKERS:min", "EMPTY:nonempty"}, keys)
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"reflect"
+
+	"github.com/go-pogo/env/envtag"
+	"github.com/go-pogo/errors"
+)
+
+// ErrUnknownVariant is returned when the value of a discriminator environment
+// variable, or the type of an interface field's value, does not match any of
+// the variants registered for the interface type.
+const ErrUnknownVariant errors.Msg = "unknown variant"
+
+const (
+	panicVariantIface  = "env: variant must be registered for an interface type"
+	panicVariantStruct = "env: variant must be a struct which implements the interface"
+)
+
+// variants contains the variants registered for an interface type.
+type variants struct {
+	types map[string]reflect.Type
+	names map[reflect.Type]string
+}
+
+// RegisterVariant registers the type of v as the variant with name of
+// interface type iface. Fields of type iface which have the disc tag option
+// are decoded into the variant of which the name equals the value of the
+// field's discriminator environment variable, e.g. with
+// `env:"STORAGE,disc=DRIVER"` the variant is selected by STORAGE_DRIVER. The
+// fields of the variant are prefixed with the name of the field.
+//
+//	reg.RegisterVariant(reflect.TypeOf((*Storage)(nil)).Elem(), "s3", S3Config{})
+func (r *Registry) RegisterVariant(iface reflect.Type, name string, v any) *Registry {
+	if iface == nil || iface.Kind() != reflect.Interface {
+		panic(panicVariantIface)
+	}
+
+	typ := reflect.TypeOf(v)
+	if typ == nil || underlyingKind(typ) != reflect.Struct || !typ.Implements(iface) {
+		panic(panicVariantStruct)
+	}
+
+	if r.variants == nil {
+		r.variants = make(map[reflect.Type]*variants, 2)
+	}
+	vars, ok := r.variants[iface]
+	if !ok {
+		vars = &variants{
+			types: make(map[string]reflect.Type, 2),
+			names: make(map[reflect.Type]string, 2),
+		}
+		r.variants[iface] = vars
+	}
+
+	vars.types[name] = typ
+	if _, ok = vars.names[typ]; !ok {
+		vars.names[typ] = name
+	}
+	return r
+}
+
+// Variant returns the type which is registered as the variant with name of
+// interface type iface.
+func (r *Registry) Variant(iface reflect.Type, name string) (reflect.Type, bool) {
+	for reg := r; reg != nil; reg = reg.parent {
+		if vars, ok := reg.variants[iface]; ok {
+			if typ, ok := vars.types[name]; ok {
+				return typ, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// variantName returns the name with which typ is registered as a variant of
+// interface type iface.
+func (r *Registry) variantName(iface, typ reflect.Type) (string, bool) {
+	for reg := r; reg != nil; reg = reg.parent {
+		if vars, ok := reg.variants[iface]; ok {
+			if name, ok := vars.names[typ]; ok {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// discriminatorKey returns the name of the environment variable which selects
+// the variant of an interface field.
+func discriminatorKey(tag envtag.Tag) string {
+	return tag.Name + "_" + tag.Discriminator
+}
+
+// variantValue returns the concrete value of interface field rv together with
+// the name of its variant, or an invalid [reflect.Value] when rv is nil.
+func (r *Registry) variantValue(rv reflect.Value, field fieldInfo) (reflect.Value, string, error) {
+	if rv.IsNil() {
+		return reflect.Value{}, "", nil
+	}
+
+	el := rv.Elem()
+	name, ok := r.variantName(field.Type, el.Type())
+	if !ok {
+		return reflect.Value{}, "", errors.Wrap(ErrUnknownVariant, el.Type().String())
+	}
+	return el, name, nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package env
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storageConfig interface{ storage() }
+
+type s3Config struct {
+	Bucket string
+	Region string `default:"eu-west-1"`
+}
+
+func (s3Config) storage() {}
+
+type gcsConfig struct {
+	Bucket  string `env:",required"`
+	Project string
+}
+
+func (*gcsConfig) storage() {}
+
+var storageConfigType = reflect.TypeOf((*storageConfig)(nil)).Elem()
+
+func storageRegistry() *Registry {
+	return NewRegistry().
+		RegisterVariant(storageConfigType, "s3", s3Config{}).
+		RegisterVariant(storageConfigType, "gcs", &gcsConfig{})
+}
+
+type variantConfig struct {
+	Name    string
+	Storage storageConfig `env:",disc=DRIVER"`
+}
+
+func TestRegistry_RegisterVariant(t *testing.T) {
+	reg := storageRegistry()
+
+	typ, ok := reg.Variant(storageConfigType, "s3")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(s3Config{}), typ)
+
+	_, ok = reg.Variant(storageConfigType, "foo")
+	assert.False(t, ok)
+	_, ok = NewRegistry().Variant(storageConfigType, "s3")
+	assert.False(t, ok)
+
+	t.Run("panics", func(t *testing.T) {
+		assert.PanicsWithValue(t, panicVariantIface, func() {
+			NewRegistry().RegisterVariant(reflect.TypeOf(s3Config{}), "s3", s3Config{})
+		})
+		assert.PanicsWithValue(t, panicVariantStruct, func() {
+			// only a pointer implements the interface
+			NewRegistry().RegisterVariant(storageConfigType, "gcs", gcsConfig{})
+		})
+	})
+}
+
+func TestDecoder_Decode_variant(t *testing.T) {
+	tests := map[string]struct {
+		src     Map
+		want    variantConfig
+		wantErr error
+	}{
+		"s3": {
+			src: Map{"STORAGE_DRIVER": "s3", "STORAGE_BUCKET": "foo"},
+			want: variantConfig{Storage: s3Config{
+				Bucket: "foo",
+				Region: "eu-west-1",
+			}},
+		},
+		"gcs": {
+			src: Map{
+				"STORAGE_DRIVER":  "gcs",
+				"STORAGE_BUCKET":  "bar",
+				"STORAGE_PROJECT": "baz",
+			},
+			want: variantConfig{Storage: &gcsConfig{
+				Bucket:  "bar",
+				Project: "baz",
+			}},
+		},
+		"missing required": {
+			src:     Map{"STORAGE_DRIVER": "gcs"},
+			want:    variantConfig{Storage: &gcsConfig{}},
+			wantErr: ErrMissingRequired,
+		},
+		"no discriminator": {
+			src:  Map{"NAME": "foo", "STORAGE_BUCKET": "foo"},
+			want: variantConfig{Name: "foo"},
+		},
+		"unknown": {
+			src:     Map{"STORAGE_DRIVER": "azure"},
+			wantErr: ErrUnknownVariant,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var have variantConfig
+			err := NewDecoder(tc.src).
+				WithRegistry(storageRegistry()).
+				Decode(&have)
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+			assert.Equal(t, tc.want, have)
+		})
+	}
+
+	t.Run("keep existing", func(t *testing.T) {
+		have := variantConfig{Storage: s3Config{Bucket: "foo"}}
+		assert.NoError(t, NewDecoder(Map{"STORAGE_REGION": "us-east-1"}).
+			WithRegistry(storageRegistry()).
+			Decode(&have),
+		)
+		assert.Equal(t, s3Config{Bucket: "foo", Region: "us-east-1"}, have.Storage)
+	})
+	t.Run("keep nil pointers", func(t *testing.T) {
+		var have variantConfig
+		err := NewDecoder(Map{"STORAGE_DRIVER": "gcs", "STORAGE_BUCKET": "foo"}).
+			WithRegistry(storageRegistry()).
+			WithOptions(DecodeOptions{KeepNilPointers: true}).
+			Decode(&have)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &gcsConfig{Bucket: "foo"}, have.Storage)
+
+		have = variantConfig{}
+		err = NewDecoder(Map{"STORAGE_DRIVER": "gcs"}).
+			WithRegistry(storageRegistry()).
+			WithOptions(DecodeOptions{KeepNilPointers: true}).
+			Decode(&have)
+
+		assert.ErrorIs(t, err, ErrMissingRequired)
+		assert.NotNil(t, have.Storage)
+		assert.Equal(t, &gcsConfig{}, have.Storage)
+	})
+	t.Run("disallow unknown", func(t *testing.T) {
+		var have variantConfig
+		assert.NoError(t, NewDecoder(Map{"STORAGE_DRIVER": "s3", "STORAGE_BUCKET": "foo"}).
+			WithRegistry(storageRegistry()).
+			DisallowUnknownVars("").
+			Decode(&have),
+		)
+	})
+}
+
+func TestEncoder_Encode_variant(t *testing.T) {
+	tests := map[string]struct {
+		input variantConfig
+		want  string
+	}{
+		"s3": {
+			input: variantConfig{Name: "foo", Storage: s3Config{Bucket: "bar"}},
+			want:  "NAME=foo\nSTORAGE_DRIVER=s3\nSTORAGE_BUCKET=bar\nSTORAGE_REGION=eu-west-1\n",
+		},
+		"gcs": {
+			input: variantConfig{Storage: &gcsConfig{Project: "baz"}},
+			want:  "NAME=\nSTORAGE_DRIVER=gcs\nSTORAGE_BUCKET=\nSTORAGE_PROJECT=baz\n",
+		},
+		"nil": {
+			want: "NAME=\nSTORAGE_DRIVER=\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, NewEncoder(&buf).
+				WithOptions(EncodeOptions{TakeValues: true}).
+				WithRegistry(storageRegistry()).
+				Encode(tc.input),
+			)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("unregistered", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.ErrorIs(t, NewEncoder(&buf).
+			Encode(variantConfig{Storage: s3Config{}}),
+			ErrUnknownVariant,
+		)
+	})
+}
+
+func TestExtractor_Extract_variant(t *testing.T) {
+	have, err := NewExtractor().
+		WithRegistry(storageRegistry()).
+		Extract(variantConfig{Storage: s3Config{Bucket: "foo"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"NAME":           "",
+		"STORAGE_DRIVER": "s3",
+		"STORAGE_BUCKET": "foo",
+		"STORAGE_REGION": "eu-west-1",
+	}, have)
+}